@@ -1,32 +1,161 @@
 package api
 
 import (
+	"context"
+	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"swapi/clients/swapi"
+	"swapi/config"
+	"swapi/httphelpers"
+	"swapi/logging"
+	"swapi/tracing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
 
 type Api struct {
-	Server http.Server
+	Server *http.Server
+	Client swapi.Client
+	Logger *logging.Logger
+	// Tracer is nil when tracing is disabled
+	Tracer *tracing.Tracer
+	// ShutdownTimeout bounds how long Run waits for in-flight requests once
+	// its context is done
+	ShutdownTimeout time.Duration
 }
 
-func (s *Api) Run() error {
-	if err := s.Server.ListenAndServe(); err != nil {
+// Run listens on the configured address and serves until ctx is done, then
+// shuts down gracefully
+func (s *Api) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.Server.Addr)
+
+	if err != nil {
+		swapi.Close(s.Client)
+		s.Tracer.Close()
+
 		return err
 	}
 
-	return nil
+	s.Logger.Info("listening", logging.Fields{"addr": listener.Addr().String()})
+
+	return s.Serve(ctx, listener)
 }
 
-func New() *Api {
+// Serve accepts connections on listener until ctx is done. It then stops
+// accepting new connections, waits up to ShutdownTimeout for in-flight
+// requests and closes the client. A clean shutdown returns nil.
+func (s *Api) Serve(ctx context.Context, listener net.Listener) error {
+	served := make(chan error, 1)
+
+	go func() {
+		served <- s.Server.Serve(listener)
+	}()
+
+	select {
+	case err := <-served:
+		swapi.Close(s.Client)
+		s.Tracer.Close()
+
+		if err == http.ErrServerClosed {
+			return nil
+		}
+
+		return err
+	case <-ctx.Done():
+	}
+
+	s.Logger.Info("shutting down", logging.Fields{"timeout": s.ShutdownTimeout.String()})
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), s.ShutdownTimeout)
+	defer cancel()
+
+	err := s.Shutdown(drainCtx)
+	<-served
+
+	return err
+}
+
+// Shutdown stops the server, waiting for in-flight requests until ctx is
+// done, closes the client and flushes the spans not exported yet. Connections
+// still open when ctx is done are closed forcibly.
+func (s *Api) Shutdown(ctx context.Context) error {
+	err := s.Server.Shutdown(ctx)
+
+	if err != nil {
+		s.Server.Close()
+		err = fmt.Errorf("draining connections: %w", err)
+	}
+
+	if closeErr := swapi.Close(s.Client); err == nil && closeErr != nil {
+		err = fmt.Errorf("closing client: %w", closeErr)
+	}
+
+	if closeErr := s.Tracer.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("closing tracer: %w", closeErr)
+	}
+
+	return err
+}
+
+func New(cfg config.Config) *Api {
+	client := NewClient(cfg)
+	swapi.Instance = client
+
+	level, _ := logging.ParseLevel(cfg.Log.Level)
+	logger := logging.New(os.Stdout, level)
+
+	tracer := NewTracer(cfg, logger)
+	tracing.Instance = tracer
+
+	httphelpers.ProblemDetails = cfg.Server.ProblemDetails
+
 	router := chi.NewRouter()
+	router.Use(RequestLogger(logger))
+	router.Use(RequestMetrics)
 
 	URLMapping(router)
 
 	return &Api{
-		Server: http.Server{
-			Addr:    ":3000",
+		Server: &http.Server{
+			Addr:    cfg.Server.Addr,
 			Handler: router,
 		},
+		Client:          client,
+		Logger:          logger,
+		Tracer:          tracer,
+		ShutdownTimeout: time.Duration(cfg.Server.ShutdownTimeout),
 	}
 }
+
+// NewTracer builds the tracer of the configured exporter, nil when tracing is
+// disabled
+func NewTracer(cfg config.Config, logger *logging.Logger) *tracing.Tracer {
+	switch cfg.Tracing.Exporter {
+	case "stdout":
+		return tracing.New(tracing.NewWriterExporter(os.Stdout))
+	case "otlp":
+		options := cfg.OTLPOptions()
+		options.OnError = func(err error) {
+			logger.Warn("exporting spans", logging.Fields{"error": err.Error()})
+		}
+
+		return tracing.New(tracing.NewOTLPExporter(options))
+	default:
+		return nil
+	}
+}
+
+// NewClient builds the SWAPI client stack: coalescing of concurrent calls in
+// front of the HTTP client, and the cache in front of both when enabled
+func NewClient(cfg config.Config) swapi.Client {
+	var client swapi.Client = swapi.NewCoalescingClient(swapi.NewSWAPIClient(cfg.ClientOptions()))
+
+	if cfg.Cache.Enabled {
+		client = swapi.NewCachedClient(client, cfg.CacheOptions())
+	}
+
+	return client
+}
@@ -1,32 +1,165 @@
 package api
 
 import (
+	"context"
+	"net"
+	"net/http"
+	"swapi/clients/swapi"
+	"swapi/config"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
+// closeRecorder is a client that counts how many times it was closed
+type closeRecorder struct {
+	swapi.MockClient
+	closed int
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed++
+
+	return nil
+}
+
+func newTestApi(t *testing.T, handler http.Handler, shutdownTimeout time.Duration) (*Api, *closeRecorder, net.Listener) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	client := &closeRecorder{}
+
+	return &Api{
+		Server:          &http.Server{Handler: handler},
+		Client:          client,
+		ShutdownTimeout: shutdownTimeout,
+	}, client, listener
+}
+
 // Test Run()
 func TestRun(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
-		api := New()
-		var err error
+		previous := swapi.Instance
+		defer func() { swapi.Instance = previous }()
 
-		running := make(chan struct{})
-		done := make(chan struct{})
+		cfg := config.Default()
+		cfg.Server.Addr = "127.0.0.1:0"
+		cfg.Log.Level = "error"
 
-		go func() {
-			close(running)
-			err = api.Run()
-			defer close(done)
-		}()
+		api := New(cfg)
 
-		<-running
 		assert.NotNil(t, api.Server)
 		assert.NotNil(t, api.Server.Handler)
-		assert.NotNil(t, api.Server.Addr)
-		assert.Nil(t, err)
-		assert.NoError(t, api.Server.Close())
-		<-done
+		assert.Equal(t, cfg.Server.Addr, api.Server.Addr)
+		assert.Equal(t, swapi.Instance, api.Client)
+		assert.Equal(t, 15*time.Second, api.ShutdownTimeout)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+
+		go func() {
+			done <- api.Run(ctx)
+		}()
+
+		cancel()
+		assert.NoError(t, <-done)
+	})
+
+	t.Run("Listen error", func(t *testing.T) {
+		client := &closeRecorder{}
+		api := &Api{
+			Server: &http.Server{Addr: "127.0.0.1:-1"},
+			Client: client,
+		}
+
+		err := api.Run(context.Background())
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, client.closed)
+	})
+}
+
+// Test Serve()
+func TestServe(t *testing.T) {
+	t.Run("Drains in-flight requests", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		api, client, listener := newTestApi(t, http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			rw.WriteHeader(http.StatusOK)
+		}), time.Minute)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+
+		go func() {
+			done <- api.Serve(ctx, listener)
+		}()
+
+		responses := make(chan *http.Response, 1)
+
+		go func() {
+			res, err := http.Get("http://" + listener.Addr().String())
+			assert.NoError(t, err)
+			responses <- res
+		}()
+
+		<-started
+		cancel()
+
+		// new connections are refused while the in-flight request drains
+		assert.Eventually(t, func() bool {
+			conn, err := net.Dial("tcp", listener.Addr().String())
+
+			if err == nil {
+				conn.Close()
+			}
+
+			return err != nil
+		}, time.Second, 5*time.Millisecond)
+
+		close(release)
+
+		res := <-responses
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		res.Body.Close()
+
+		assert.NoError(t, <-done)
+		assert.Equal(t, 1, client.closed)
+	})
+
+	t.Run("Drain timeout", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+
+		api, client, listener := newTestApi(t, http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+		}), 10*time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+
+		go func() {
+			done <- api.Serve(ctx, listener)
+		}()
+
+		go func() {
+			res, err := http.Get("http://" + listener.Addr().String())
+
+			if err == nil {
+				res.Body.Close()
+			}
+		}()
+
+		<-started
+		cancel()
+
+		assert.EqualError(t, <-done, "draining connections: context deadline exceeded")
+		assert.Equal(t, 1, client.closed)
 	})
 }
@@ -0,0 +1,35 @@
+package api
+
+import "swapi/services"
+
+// embedExpansion returns v with the links of every expanded field replaced
+// by the resources they point at. Links that couldn't be fetched are left in
+// place and listed under expand_errors.
+func embedExpansion(v interface{}, expansion services.Expansion) interface{} {
+	tree, ok := toTree(v).(map[string]interface{})
+
+	if !ok {
+		return v
+	}
+
+	for field, resources := range expansion.Resources {
+		switch value := tree[field].(type) {
+		case []interface{}:
+			for i, resource := range resources {
+				if resource != nil && i < len(value) {
+					value[i] = resource
+				}
+			}
+		case string:
+			if len(resources) == 1 && resources[0] != nil {
+				tree[field] = resources[0]
+			}
+		}
+	}
+
+	if len(expansion.Errors) > 0 {
+		tree["expand_errors"] = expansion.Errors
+	}
+
+	return tree
+}
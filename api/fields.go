@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// jsonFields lists the JSON names of the fields of model, in declaration
+// order
+func jsonFields(model interface{}) []string {
+	t := reflect.TypeOf(model)
+	fields := make([]string, 0, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+
+	return fields
+}
+
+// queryFields reads the fields query parameter, which must only name fields
+// of model
+func queryFields(r *http.Request, model interface{}) ([]string, error) {
+	return queryList(r, "fields", jsonFields(model))
+}
+
+// selectFields returns v with only the given fields, applied to every item of
+// results for lists. An empty selection keeps every field.
+func selectFields(v interface{}, fields []string) interface{} {
+	if len(fields) == 0 {
+		return v
+	}
+
+	tree, ok := toTree(v).(map[string]interface{})
+
+	if !ok {
+		return v
+	}
+
+	results, isList := tree["results"].([]interface{})
+
+	if !isList {
+		keepFields(tree, fields)
+		return tree
+	}
+
+	for _, item := range results {
+		if resource, ok := item.(map[string]interface{}); ok {
+			keepFields(resource, fields)
+		}
+	}
+
+	return tree
+}
+
+func keepFields(resource map[string]interface{}, fields []string) {
+	for key := range resource {
+		// failed expansions are reported whatever the selection
+		if key != "expand_errors" && !contains(fields, key) {
+			delete(resource, key)
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"strconv"
 	"swapi/errors"
 	"swapi/httphelpers"
+	"swapi/logging"
+	"swapi/models"
 	"swapi/services"
 
 	"github.com/go-chi/chi/v5"
@@ -14,76 +16,631 @@ func GetStarshipHandler(rw http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 
 	if err != nil {
-		httphelpers.BadRequest(rw, errors.NewBadRequest("invalid id"))
+		httphelpers.Error(rw, r, errors.NewBadRequest("invalid id"))
 		return
 	}
 
-	result, err := services.GetStarshipService(id)
+	links, err := queryLinks(r)
 
 	if err != nil {
-		if errors.Status(err) == http.StatusNotFound {
-			httphelpers.NotFound(rw, err)
-			return
-		} else {
-			httphelpers.InternalServerError(rw)
-			return
-		}
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	expand, err := queryList(r, "expand", services.StarshipExpandFields())
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
 	}
 
-	httphelpers.OK(rw, result)
+	typed, err := typedFormat(r)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	var model interface{} = models.Starship{}
+
+	if typed {
+		model = models.TypedStarship{}
+	}
+
+	fields, err := queryFields(r, model)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	result, err := services.GetStarshipService(r.Context(), id)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	var response interface{} = result
+
+	if typed {
+		response = result.Typed()
+	}
+
+	if len(expand) > 0 {
+		response = embedExpansion(response, services.ExpandStarshipService(r.Context(), result, expand))
+	}
+
+	httphelpers.OK(rw, r, rewriteLinks(r, links, selectFields(response, fields)))
 }
 
 func GetStarshipsHandler(rw http.ResponseWriter, r *http.Request) {
-	result, err := services.GetStarshipsService()
+	all, err := queryBool(r, "all")
+
+	if err != nil {
+		httphelpers.Error(rw, r, errors.NewBadRequest("invalid all"))
+		return
+	}
+
+	links, err := queryLinks(r)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	typed, err := typedFormat(r)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	var model interface{} = models.Starship{}
+
+	if typed {
+		model = models.TypedStarship{}
+	}
+
+	fields, err := queryFields(r, model)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	page, pageSize, err := pagination(r)
 
 	if err != nil {
-		if errors.Status(err) == http.StatusNotFound {
-			httphelpers.NotFound(rw, err)
-			return
-		} else {
-			httphelpers.InternalServerError(rw)
-			return
-		}
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	search := r.URL.Query().Get("search")
+	query := listQuery(r)
+
+	var result models.Starships
+
+	switch {
+	case !query.Empty() && all:
+		result, err = services.QueryStarshipsService(r.Context(), search, query, 1, 0)
+	case !query.Empty():
+		result, err = services.QueryStarshipsService(r.Context(), search, query, page, pageSize)
+	case search != "" && all:
+		result, err = services.SearchStarshipsService(r.Context(), search, 1, 0)
+	case search != "":
+		result, err = services.SearchStarshipsService(r.Context(), search, page, pageSize)
+	case all:
+		result, err = services.GetAllStarshipsService(r.Context())
+	default:
+		result, err = services.GetStarshipsService(r.Context(), page, pageSize)
 	}
 
-	httphelpers.OK(rw, result)
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	if !all {
+		result.Next, result.Previous = pageLinks(r, page, pageSize, result.Count)
+	}
+
+	var response interface{} = result
+
+	if typed {
+		response = result.Typed()
+	}
+
+	httphelpers.OK(rw, r, rewriteLinks(r, links, selectFields(response, fields)))
 }
 
 func GetPeopleHandler(rw http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 
 	if err != nil {
-		httphelpers.BadRequest(rw, errors.NewBadRequest("invalid id"))
+		httphelpers.Error(rw, r, errors.NewBadRequest("invalid id"))
 		return
 	}
 
-	result, err := services.GetPeopleService(id)
+	links, err := queryLinks(r)
 
 	if err != nil {
-		if errors.Status(err) == http.StatusNotFound {
-			httphelpers.NotFound(rw, err)
-			return
-		} else {
-			httphelpers.InternalServerError(rw)
-			return
-		}
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	expand, err := queryList(r, "expand", services.PeopleExpandFields())
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	typed, err := typedFormat(r)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	var model interface{} = models.People{}
+
+	if typed {
+		model = models.TypedPeople{}
+	}
+
+	fields, err := queryFields(r, model)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	result, err := services.GetPeopleService(r.Context(), id)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
 	}
 
-	httphelpers.OK(rw, result)
+	var response interface{} = result
+
+	if typed {
+		response = result.Typed()
+	}
+
+	if len(expand) > 0 {
+		response = embedExpansion(response, services.ExpandPeopleService(r.Context(), result, expand))
+	}
+
+	httphelpers.OK(rw, r, rewriteLinks(r, links, selectFields(response, fields)))
 }
 
 func GetPeopleListHandler(rw http.ResponseWriter, r *http.Request) {
-	result, err := services.GetPeopleListService()
+	all, err := queryBool(r, "all")
+
+	if err != nil {
+		httphelpers.Error(rw, r, errors.NewBadRequest("invalid all"))
+		return
+	}
+
+	links, err := queryLinks(r)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	typed, err := typedFormat(r)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	var model interface{} = models.People{}
+
+	if typed {
+		model = models.TypedPeople{}
+	}
+
+	fields, err := queryFields(r, model)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	page, pageSize, err := pagination(r)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	search := r.URL.Query().Get("search")
+	query := listQuery(r)
+
+	var result models.PeopleList
+
+	switch {
+	case !query.Empty() && all:
+		result, err = services.QueryPeopleService(r.Context(), search, query, 1, 0)
+	case !query.Empty():
+		result, err = services.QueryPeopleService(r.Context(), search, query, page, pageSize)
+	case search != "" && all:
+		result, err = services.SearchPeopleService(r.Context(), search, 1, 0)
+	case search != "":
+		result, err = services.SearchPeopleService(r.Context(), search, page, pageSize)
+	case all:
+		result, err = services.GetAllPeopleService(r.Context())
+	default:
+		result, err = services.GetPeopleListService(r.Context(), page, pageSize)
+	}
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	if !all {
+		result.Next, result.Previous = pageLinks(r, page, pageSize, result.Count)
+	}
+
+	var response interface{} = result
+
+	if typed {
+		response = result.Typed()
+	}
+
+	httphelpers.OK(rw, r, rewriteLinks(r, links, selectFields(response, fields)))
+}
+
+func GetFilmHandler(rw http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		httphelpers.Error(rw, r, errors.NewBadRequest("invalid id"))
+		return
+	}
+
+	links, err := queryLinks(r)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	fields, err := queryFields(r, models.Film{})
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	result, err := services.GetFilmService(r.Context(), id)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	httphelpers.OK(rw, r, rewriteLinks(r, links, selectFields(result, fields)))
+}
+
+func GetFilmsHandler(rw http.ResponseWriter, r *http.Request) {
+	all, err := queryBool(r, "all")
+
+	if err != nil {
+		httphelpers.Error(rw, r, errors.NewBadRequest("invalid all"))
+		return
+	}
+
+	links, err := queryLinks(r)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	fields, err := queryFields(r, models.Film{})
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	page, pageSize, err := pagination(r)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	var result models.Films
+
+	if all {
+		result, err = services.GetAllFilmsService(r.Context())
+	} else {
+		result, err = services.GetFilmsService(r.Context(), page, pageSize)
+	}
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	if !all {
+		result.Next, result.Previous = pageLinks(r, page, pageSize, result.Count)
+	}
+
+	httphelpers.OK(rw, r, rewriteLinks(r, links, selectFields(result, fields)))
+}
+
+func GetPlanetHandler(rw http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		httphelpers.Error(rw, r, errors.NewBadRequest("invalid id"))
+		return
+	}
+
+	links, err := queryLinks(r)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	fields, err := queryFields(r, models.Planet{})
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	result, err := services.GetPlanetService(r.Context(), id)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	httphelpers.OK(rw, r, rewriteLinks(r, links, selectFields(result, fields)))
+}
+
+func GetPlanetsHandler(rw http.ResponseWriter, r *http.Request) {
+	all, err := queryBool(r, "all")
+
+	if err != nil {
+		httphelpers.Error(rw, r, errors.NewBadRequest("invalid all"))
+		return
+	}
+
+	links, err := queryLinks(r)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	fields, err := queryFields(r, models.Planet{})
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	page, pageSize, err := pagination(r)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	var result models.Planets
+
+	if all {
+		result, err = services.GetAllPlanetsService(r.Context())
+	} else {
+		result, err = services.GetPlanetsService(r.Context(), page, pageSize)
+	}
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	if !all {
+		result.Next, result.Previous = pageLinks(r, page, pageSize, result.Count)
+	}
+
+	httphelpers.OK(rw, r, rewriteLinks(r, links, selectFields(result, fields)))
+}
+
+func GetSpeciesHandler(rw http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		httphelpers.Error(rw, r, errors.NewBadRequest("invalid id"))
+		return
+	}
+
+	links, err := queryLinks(r)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	fields, err := queryFields(r, models.Species{})
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	result, err := services.GetSpeciesService(r.Context(), id)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	httphelpers.OK(rw, r, rewriteLinks(r, links, selectFields(result, fields)))
+}
+
+func GetSpeciesListHandler(rw http.ResponseWriter, r *http.Request) {
+	all, err := queryBool(r, "all")
 
 	if err != nil {
-		if errors.Status(err) == http.StatusNotFound {
-			httphelpers.NotFound(rw, err)
-			return
-		} else {
-			httphelpers.InternalServerError(rw)
-			return
-		}
+		httphelpers.Error(rw, r, errors.NewBadRequest("invalid all"))
+		return
+	}
+
+	links, err := queryLinks(r)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	fields, err := queryFields(r, models.Species{})
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	page, pageSize, err := pagination(r)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	var result models.SpeciesList
+
+	if all {
+		result, err = services.GetAllSpeciesService(r.Context())
+	} else {
+		result, err = services.GetSpeciesListService(r.Context(), page, pageSize)
+	}
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	if !all {
+		result.Next, result.Previous = pageLinks(r, page, pageSize, result.Count)
+	}
+
+	httphelpers.OK(rw, r, rewriteLinks(r, links, selectFields(result, fields)))
+}
+
+func GetVehicleHandler(rw http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		httphelpers.Error(rw, r, errors.NewBadRequest("invalid id"))
+		return
+	}
+
+	links, err := queryLinks(r)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	fields, err := queryFields(r, models.Vehicle{})
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	result, err := services.GetVehicleService(r.Context(), id)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	httphelpers.OK(rw, r, rewriteLinks(r, links, selectFields(result, fields)))
+}
+
+func GetVehiclesHandler(rw http.ResponseWriter, r *http.Request) {
+	all, err := queryBool(r, "all")
+
+	if err != nil {
+		httphelpers.Error(rw, r, errors.NewBadRequest("invalid all"))
+		return
+	}
+
+	links, err := queryLinks(r)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	fields, err := queryFields(r, models.Vehicle{})
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	page, pageSize, err := pagination(r)
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	var result models.Vehicles
+
+	if all {
+		result, err = services.GetAllVehiclesService(r.Context())
+	} else {
+		result, err = services.GetVehiclesService(r.Context(), page, pageSize)
+	}
+
+	if err != nil {
+		httphelpers.Error(rw, r, err)
+		return
+	}
+
+	if !all {
+		result.Next, result.Previous = pageLinks(r, page, pageSize, result.Count)
+	}
+
+	httphelpers.OK(rw, r, rewriteLinks(r, links, selectFields(result, fields)))
+}
+
+func GetDiagnosticsHandler(rw http.ResponseWriter, r *http.Request) {
+	httphelpers.OK(rw, r, services.GetDiagnosticsService())
+}
+
+// GetHealthHandler tells the process is alive, without checking dependencies.
+// Probes get JSON whatever they accept.
+func GetHealthHandler(rw http.ResponseWriter, r *http.Request) {
+	httphelpers.JSON(rw, http.StatusOK, map[string]string{"status": services.StatusOK})
+}
+
+// GetReadinessHandler tells whether the dependencies needed to serve requests
+// are reachable, answering 503 when one isn't. Probes get JSON whatever they
+// accept, and the error of a failing check is logged with the request.
+func GetReadinessHandler(rw http.ResponseWriter, r *http.Request) {
+	readiness := services.ReadinessService(r.Context())
+
+	if !readiness.Ready() {
+		logging.SetServedError(r.Context(), readiness.Err())
+		httphelpers.JSON(rw, http.StatusServiceUnavailable, readiness)
+		return
 	}
 
-	httphelpers.OK(rw, result)
+	httphelpers.JSON(rw, http.StatusOK, readiness)
 }
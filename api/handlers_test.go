@@ -1,14 +1,23 @@
 package api
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"swapi/clients/swapi"
 	"swapi/errors"
+	"swapi/httphelpers"
+	"swapi/logging"
 	"swapi/mockeable"
 	"swapi/models"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -54,13 +63,13 @@ func TestGetStarshipHandler(t *testing.T) {
 			Name:                 "Bad Request",
 			ID:                   "invalid_id",
 			ExpectedStatusCode:   http.StatusBadRequest,
-			ExpectedResponseBody: `{"type":"BAD_REQUEST","message":"Bad request. Reason: invalid id"}`,
+			ExpectedResponseBody: `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid id"}`,
 		},
 		{
 			Name:                      "Not Found",
 			ID:                        1,
 			ExpectedStatusCode:        http.StatusNotFound,
-			ExpectedResponseBody:      `{"type":"NOT_FOUND","message":"resource: starships with id: 1 not found"}`,
+			ExpectedResponseBody:      `{"type":"NOT_FOUND","code":"resource_not_found","message":"resource: starships with id: 1 not found"}`,
 			ExpectedMockErrorResponse: errors.NewNotFound("starships", "1"),
 			ExpectedMockCallCount:     1,
 		},
@@ -68,10 +77,26 @@ func TestGetStarshipHandler(t *testing.T) {
 			Name:                      "Internal Server Error",
 			ID:                        1,
 			ExpectedStatusCode:        http.StatusInternalServerError,
-			ExpectedResponseBody:      `{"type":"INTERNAL_SERVER_ERROR","message":"Internal server error."}`,
+			ExpectedResponseBody:      `{"type":"INTERNAL_SERVER_ERROR","code":"internal_error","message":"Internal server error."}`,
 			ExpectedMockErrorResponse: errors.NewInternal(),
 			ExpectedMockCallCount:     1,
 		},
+		{
+			Name:                      "Gateway Timeout",
+			ID:                        1,
+			ExpectedStatusCode:        http.StatusGatewayTimeout,
+			ExpectedResponseBody:      `{"type":"GATEWAY_TIMEOUT","code":"upstream_timeout","message":"Gateway timeout. The upstream server didn't respond in time."}`,
+			ExpectedMockErrorResponse: errors.NewGatewayTimeout(),
+			ExpectedMockCallCount:     1,
+		},
+		{
+			Name:                      "Upstream Unavailable",
+			ID:                        1,
+			ExpectedStatusCode:        http.StatusServiceUnavailable,
+			ExpectedResponseBody:      `{"type":"UPSTREAM_UNAVAILABLE","code":"upstream_circuit_open","message":"Upstream unavailable. Try again later."}`,
+			ExpectedMockErrorResponse: errors.NewUpstreamUnavailable(),
+			ExpectedMockCallCount:     1,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -79,7 +104,7 @@ func TestGetStarshipHandler(t *testing.T) {
 
 			// Create client mock
 			swapiMock := swapi.MockClient{
-				GetStarshipFunc: func(id int) (models.Starship, error) {
+				GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
 					assert.Equal(t, tc.ID, id)
 
 					return tc.ExpectedMockSuccessResponse, tc.ExpectedMockErrorResponse
@@ -147,14 +172,14 @@ func TestGetStarships(t *testing.T) {
 		{
 			Name:                      "Not Found",
 			ExpectedMockErrorResponse: errors.NewNotFound("starships", ""),
-			ExpectedResponseBody:      `{"type":"NOT_FOUND","message":"resource: starships not found"}`,
+			ExpectedResponseBody:      `{"type":"NOT_FOUND","code":"resource_not_found","message":"resource: starships not found"}`,
 			ExpectedMockCallCount:     1,
 			ExpectedStatusCode:        http.StatusNotFound,
 		},
 		{
 			Name:                      "Internal Server Error",
 			ExpectedMockErrorResponse: errors.NewInternal(),
-			ExpectedResponseBody:      `{"type":"INTERNAL_SERVER_ERROR","message":"Internal server error."}`,
+			ExpectedResponseBody:      `{"type":"INTERNAL_SERVER_ERROR","code":"internal_error","message":"Internal server error."}`,
 			ExpectedMockCallCount:     1,
 			ExpectedStatusCode:        http.StatusInternalServerError,
 		},
@@ -165,7 +190,8 @@ func TestGetStarships(t *testing.T) {
 
 			// Create client mock
 			swapiMock := swapi.MockClient{
-				GetStarshipsFunc: func() (models.Starships, error) {
+				GetStarshipsFunc: func(ctx context.Context, page int) (models.Starships, error) {
+					assert.Equal(t, 1, page)
 					return tc.ExpectedMockSuccessResponse, tc.ExpectedMockErrorResponse
 				},
 				GetStarshipsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedMockCallCount},
@@ -233,7 +259,7 @@ func TestGetPeople(t *testing.T) {
 			Name:                      "Not Found",
 			ID:                        1,
 			ExpectedMockErrorResponse: errors.NewNotFound("people", "1"),
-			ExpectedResponseBody:      `{"type":"NOT_FOUND","message":"resource: people with id: 1 not found"}`,
+			ExpectedResponseBody:      `{"type":"NOT_FOUND","code":"resource_not_found","message":"resource: people with id: 1 not found"}`,
 			ExpectedStatusCode:        http.StatusNotFound,
 			ExpectedMockCallCount:     1,
 		},
@@ -241,14 +267,22 @@ func TestGetPeople(t *testing.T) {
 			Name:                      "Internal Server Error",
 			ID:                        1,
 			ExpectedMockErrorResponse: errors.NewInternal(),
-			ExpectedResponseBody:      `{"type":"INTERNAL_SERVER_ERROR","message":"Internal server error."}`,
+			ExpectedResponseBody:      `{"type":"INTERNAL_SERVER_ERROR","code":"internal_error","message":"Internal server error."}`,
 			ExpectedStatusCode:        http.StatusInternalServerError,
 			ExpectedMockCallCount:     1,
 		},
+		{
+			Name:                      "Gateway Timeout",
+			ID:                        1,
+			ExpectedMockErrorResponse: errors.NewGatewayTimeout(),
+			ExpectedResponseBody:      `{"type":"GATEWAY_TIMEOUT","code":"upstream_timeout","message":"Gateway timeout. The upstream server didn't respond in time."}`,
+			ExpectedStatusCode:        http.StatusGatewayTimeout,
+			ExpectedMockCallCount:     1,
+		},
 		{
 			Name:                      "Bad Request",
 			ExpectedMockErrorResponse: errors.NewBadRequest("invalid id"),
-			ExpectedResponseBody:      `{"type":"BAD_REQUEST","message":"Bad request. Reason: invalid id"}`,
+			ExpectedResponseBody:      `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid id"}`,
 			ExpectedStatusCode:        http.StatusBadRequest,
 		},
 	}
@@ -258,7 +292,7 @@ func TestGetPeople(t *testing.T) {
 
 			// Create client mock
 			swapiMock := swapi.MockClient{
-				GetPeopleFunc: func(id int) (models.People, error) {
+				GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
 					assert.Equal(t, tc.ID, id)
 					return tc.ExpectedMockSuccessResponse, tc.ExpectedMockErrorResponse
 				},
@@ -329,14 +363,14 @@ func TestGetPeopleList(t *testing.T) {
 		{
 			Name:                      "Not Found",
 			ExpectedMockErrorResponse: errors.NewNotFound("people", ""),
-			ExpectedResponseBody:      `{"type":"NOT_FOUND","message":"resource: people not found"}`,
+			ExpectedResponseBody:      `{"type":"NOT_FOUND","code":"resource_not_found","message":"resource: people not found"}`,
 			ExpectedStatusCode:        http.StatusNotFound,
 			ExpectedMockCallCount:     1,
 		},
 		{
 			Name:                      "Internal Server Error",
 			ExpectedMockErrorResponse: errors.NewInternal(),
-			ExpectedResponseBody:      `{"type":"INTERNAL_SERVER_ERROR","message":"Internal server error."}`,
+			ExpectedResponseBody:      `{"type":"INTERNAL_SERVER_ERROR","code":"internal_error","message":"Internal server error."}`,
 			ExpectedStatusCode:        http.StatusInternalServerError,
 			ExpectedMockCallCount:     1,
 		},
@@ -347,7 +381,8 @@ func TestGetPeopleList(t *testing.T) {
 
 			// Create client mock
 			swapiMock := swapi.MockClient{
-				GetPeopleListFunc: func() (models.PeopleList, error) {
+				GetPeopleListFunc: func(ctx context.Context, page int) (models.PeopleList, error) {
+					assert.Equal(t, 1, page)
 					return tc.ExpectedMockSuccessResponse, tc.ExpectedMockErrorResponse
 				},
 				GetPeopleListFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedMockCallCount},
@@ -368,3 +403,2010 @@ func TestGetPeopleList(t *testing.T) {
 		})
 	}
 }
+
+func TestGetFilmHandler(t *testing.T) {
+
+	type TestCase struct {
+		Name                        string
+		ID                          interface{}
+		ExpectedResponseBody        string
+		ExpectedStatusCode          int
+		ExpectedMockSuccessResponse models.Film
+		ExpectedMockErrorResponse   error
+		ExpectedMockCallCount       int
+	}
+
+	testCases := []TestCase{
+		{
+			Name:               "Success",
+			ID:                 1,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMockSuccessResponse: models.Film{
+				Title:       "A New Hope",
+				EpisodeID:   4,
+				Director:    "George Lucas",
+				Producer:    "Gary Kurtz, Rick McCallum",
+				ReleaseDate: "1977-05-25",
+				Characters: []string{
+					"https://swapi.dev/api/people/1/",
+				},
+			},
+			ExpectedResponseBody:  `{"title":"A New Hope","episode_id":4,"opening_crawl":"","director":"George Lucas","producer":"Gary Kurtz, Rick McCallum","release_date":"1977-05-25","characters":["https://swapi.dev/api/people/1/"],"planets":null,"starships":null,"vehicles":null,"species":null}`,
+			ExpectedMockCallCount: 1,
+		},
+		{
+			Name:                 "Bad Request",
+			ID:                   "invalid_id",
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedResponseBody: `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid id"}`,
+		},
+		{
+			Name:                      "Not Found",
+			ID:                        1,
+			ExpectedStatusCode:        http.StatusNotFound,
+			ExpectedResponseBody:      `{"type":"NOT_FOUND","code":"resource_not_found","message":"resource: films with id: 1 not found"}`,
+			ExpectedMockErrorResponse: errors.NewNotFound("films", "1"),
+			ExpectedMockCallCount:     1,
+		},
+		{
+			Name:                      "Internal Server Error",
+			ID:                        1,
+			ExpectedStatusCode:        http.StatusInternalServerError,
+			ExpectedResponseBody:      `{"type":"INTERNAL_SERVER_ERROR","code":"internal_error","message":"Internal server error."}`,
+			ExpectedMockErrorResponse: errors.NewInternal(),
+			ExpectedMockCallCount:     1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create client mock
+			swapiMock := swapi.MockClient{
+				GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+					assert.Equal(t, tc.ID, id)
+
+					return tc.ExpectedMockSuccessResponse, tc.ExpectedMockErrorResponse
+				},
+				GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedMockCallCount},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			// Create request
+			handlerURL := fmt.Sprintf("/api/v1/films/%v", tc.ID)
+
+			// Do request
+			response := DoRequest(http.MethodGet, handlerURL, nil, "")
+
+			// Assert response
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+			assert.JSONEq(t, tc.ExpectedResponseBody, response.StringBody())
+		})
+	}
+}
+
+func TestGetFilmsHandler(t *testing.T) {
+
+	type TestCase struct {
+		Name                        string
+		ExpectedResponseBody        string
+		ExpectedMockSuccessResponse models.Films
+		ExpectedMockErrorResponse   error
+		ExpectedMockCallCount       int
+		ExpectedStatusCode          int
+	}
+
+	testCases := []TestCase{
+		{
+			Name: "Success",
+			ExpectedMockSuccessResponse: models.Films{
+				Count: 1,
+				Results: []models.Film{
+					{
+						Title:       "A New Hope",
+						EpisodeID:   4,
+						Director:    "George Lucas",
+						Producer:    "Gary Kurtz, Rick McCallum",
+						ReleaseDate: "1977-05-25",
+						Characters: []string{
+							"https://swapi.dev/api/people/1/",
+						},
+					},
+				},
+			},
+			ExpectedResponseBody:  `{"count":1,"results":[{"title":"A New Hope","episode_id":4,"opening_crawl":"","director":"George Lucas","producer":"Gary Kurtz, Rick McCallum","release_date":"1977-05-25","characters":["https://swapi.dev/api/people/1/"],"planets":null,"starships":null,"vehicles":null,"species":null}]}`,
+			ExpectedMockCallCount: 1,
+			ExpectedStatusCode:    http.StatusOK,
+		},
+		{
+			Name:                      "Not Found",
+			ExpectedMockErrorResponse: errors.NewNotFound("films", ""),
+			ExpectedResponseBody:      `{"type":"NOT_FOUND","code":"resource_not_found","message":"resource: films not found"}`,
+			ExpectedMockCallCount:     1,
+			ExpectedStatusCode:        http.StatusNotFound,
+		},
+		{
+			Name:                      "Internal Server Error",
+			ExpectedMockErrorResponse: errors.NewInternal(),
+			ExpectedResponseBody:      `{"type":"INTERNAL_SERVER_ERROR","code":"internal_error","message":"Internal server error."}`,
+			ExpectedMockCallCount:     1,
+			ExpectedStatusCode:        http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create client mock
+			swapiMock := swapi.MockClient{
+				GetFilmsFunc: func(ctx context.Context, page int) (models.Films, error) {
+					assert.Equal(t, 1, page)
+					return tc.ExpectedMockSuccessResponse, tc.ExpectedMockErrorResponse
+				},
+				GetFilmsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedMockCallCount},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			// Create request
+			handlerURL := "/api/v1/films"
+
+			// Do request
+			response := DoRequest(http.MethodGet, handlerURL, nil, "")
+
+			// Assert response
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+			assert.JSONEq(t, tc.ExpectedResponseBody, response.StringBody())
+		})
+	}
+}
+
+func TestGetPlanetHandler(t *testing.T) {
+
+	type TestCase struct {
+		Name                        string
+		ID                          interface{}
+		ExpectedResponseBody        string
+		ExpectedStatusCode          int
+		ExpectedMockSuccessResponse models.Planet
+		ExpectedMockErrorResponse   error
+		ExpectedMockCallCount       int
+	}
+
+	testCases := []TestCase{
+		{
+			Name:               "Success",
+			ID:                 1,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMockSuccessResponse: models.Planet{
+				Name:           "Tatooine",
+				RotationPeriod: "23",
+				OrbitalPeriod:  "304",
+				Diameter:       "10465",
+				Climate:        "arid",
+				Gravity:        "1 standard",
+				Terrain:        "desert",
+				SurfaceWater:   "1",
+				Population:     "200000",
+				Residents: []string{
+					"https://swapi.dev/api/people/1/",
+				},
+			},
+			ExpectedResponseBody:  `{"name":"Tatooine","rotation_period":"23","orbital_period":"304","diameter":"10465","climate":"arid","gravity":"1 standard","terrain":"desert","surface_water":"1","population":"200000","residents":["https://swapi.dev/api/people/1/"],"films":null}`,
+			ExpectedMockCallCount: 1,
+		},
+		{
+			Name:                 "Bad Request",
+			ID:                   "invalid_id",
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedResponseBody: `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid id"}`,
+		},
+		{
+			Name:                      "Not Found",
+			ID:                        1,
+			ExpectedStatusCode:        http.StatusNotFound,
+			ExpectedResponseBody:      `{"type":"NOT_FOUND","code":"resource_not_found","message":"resource: planets with id: 1 not found"}`,
+			ExpectedMockErrorResponse: errors.NewNotFound("planets", "1"),
+			ExpectedMockCallCount:     1,
+		},
+		{
+			Name:                      "Internal Server Error",
+			ID:                        1,
+			ExpectedStatusCode:        http.StatusInternalServerError,
+			ExpectedResponseBody:      `{"type":"INTERNAL_SERVER_ERROR","code":"internal_error","message":"Internal server error."}`,
+			ExpectedMockErrorResponse: errors.NewInternal(),
+			ExpectedMockCallCount:     1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create client mock
+			swapiMock := swapi.MockClient{
+				GetPlanetFunc: func(ctx context.Context, id int) (models.Planet, error) {
+					assert.Equal(t, tc.ID, id)
+
+					return tc.ExpectedMockSuccessResponse, tc.ExpectedMockErrorResponse
+				},
+				GetPlanetFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedMockCallCount},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			// Create request
+			handlerURL := fmt.Sprintf("/api/v1/planets/%v", tc.ID)
+
+			// Do request
+			response := DoRequest(http.MethodGet, handlerURL, nil, "")
+
+			// Assert response
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+			assert.JSONEq(t, tc.ExpectedResponseBody, response.StringBody())
+		})
+	}
+}
+
+func TestGetPlanetsHandler(t *testing.T) {
+
+	type TestCase struct {
+		Name                        string
+		ExpectedResponseBody        string
+		ExpectedMockSuccessResponse models.Planets
+		ExpectedMockErrorResponse   error
+		ExpectedMockCallCount       int
+		ExpectedStatusCode          int
+	}
+
+	testCases := []TestCase{
+		{
+			Name: "Success",
+			ExpectedMockSuccessResponse: models.Planets{
+				Count: 1,
+				Results: []models.Planet{
+					{
+						Name:           "Tatooine",
+						RotationPeriod: "23",
+						OrbitalPeriod:  "304",
+						Diameter:       "10465",
+						Climate:        "arid",
+						Gravity:        "1 standard",
+						Terrain:        "desert",
+						SurfaceWater:   "1",
+						Population:     "200000",
+						Residents: []string{
+							"https://swapi.dev/api/people/1/",
+						},
+					},
+				},
+			},
+			ExpectedResponseBody:  `{"count":1,"results":[{"name":"Tatooine","rotation_period":"23","orbital_period":"304","diameter":"10465","climate":"arid","gravity":"1 standard","terrain":"desert","surface_water":"1","population":"200000","residents":["https://swapi.dev/api/people/1/"],"films":null}]}`,
+			ExpectedMockCallCount: 1,
+			ExpectedStatusCode:    http.StatusOK,
+		},
+		{
+			Name:                      "Not Found",
+			ExpectedMockErrorResponse: errors.NewNotFound("planets", ""),
+			ExpectedResponseBody:      `{"type":"NOT_FOUND","code":"resource_not_found","message":"resource: planets not found"}`,
+			ExpectedMockCallCount:     1,
+			ExpectedStatusCode:        http.StatusNotFound,
+		},
+		{
+			Name:                      "Internal Server Error",
+			ExpectedMockErrorResponse: errors.NewInternal(),
+			ExpectedResponseBody:      `{"type":"INTERNAL_SERVER_ERROR","code":"internal_error","message":"Internal server error."}`,
+			ExpectedMockCallCount:     1,
+			ExpectedStatusCode:        http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create client mock
+			swapiMock := swapi.MockClient{
+				GetPlanetsFunc: func(ctx context.Context, page int) (models.Planets, error) {
+					assert.Equal(t, 1, page)
+					return tc.ExpectedMockSuccessResponse, tc.ExpectedMockErrorResponse
+				},
+				GetPlanetsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedMockCallCount},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			// Create request
+			handlerURL := "/api/v1/planets"
+
+			// Do request
+			response := DoRequest(http.MethodGet, handlerURL, nil, "")
+
+			// Assert response
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+			assert.JSONEq(t, tc.ExpectedResponseBody, response.StringBody())
+		})
+	}
+}
+
+func TestGetSpeciesHandler(t *testing.T) {
+
+	type TestCase struct {
+		Name                        string
+		ID                          interface{}
+		ExpectedResponseBody        string
+		ExpectedStatusCode          int
+		ExpectedMockSuccessResponse models.Species
+		ExpectedMockErrorResponse   error
+		ExpectedMockCallCount       int
+	}
+
+	testCases := []TestCase{
+		{
+			Name:               "Success",
+			ID:                 1,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMockSuccessResponse: models.Species{
+				Name:            "Wookie",
+				Classification:  "mammal",
+				Designation:     "sentient",
+				AverageHeight:   "210",
+				AverageLifespan: "400",
+				EyeColors:       "blue, green, yellow, brown, golden, red",
+				HairColors:      "black, brown",
+				SkinColors:      "gray",
+				Language:        "Shyriiwook",
+				Homeworld:       "https://swapi.dev/api/planets/14/",
+			},
+			ExpectedResponseBody:  `{"name":"Wookie","classification":"mammal","designation":"sentient","average_height":"210","average_lifespan":"400","eye_colors":"blue, green, yellow, brown, golden, red","hair_colors":"black, brown","skin_colors":"gray","language":"Shyriiwook","homeworld":"https://swapi.dev/api/planets/14/","people":null,"films":null}`,
+			ExpectedMockCallCount: 1,
+		},
+		{
+			Name:                 "Bad Request",
+			ID:                   "invalid_id",
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedResponseBody: `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid id"}`,
+		},
+		{
+			Name:                      "Not Found",
+			ID:                        1,
+			ExpectedStatusCode:        http.StatusNotFound,
+			ExpectedResponseBody:      `{"type":"NOT_FOUND","code":"resource_not_found","message":"resource: species with id: 1 not found"}`,
+			ExpectedMockErrorResponse: errors.NewNotFound("species", "1"),
+			ExpectedMockCallCount:     1,
+		},
+		{
+			Name:                      "Internal Server Error",
+			ID:                        1,
+			ExpectedStatusCode:        http.StatusInternalServerError,
+			ExpectedResponseBody:      `{"type":"INTERNAL_SERVER_ERROR","code":"internal_error","message":"Internal server error."}`,
+			ExpectedMockErrorResponse: errors.NewInternal(),
+			ExpectedMockCallCount:     1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create client mock
+			swapiMock := swapi.MockClient{
+				GetSpeciesFunc: func(ctx context.Context, id int) (models.Species, error) {
+					assert.Equal(t, tc.ID, id)
+
+					return tc.ExpectedMockSuccessResponse, tc.ExpectedMockErrorResponse
+				},
+				GetSpeciesFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedMockCallCount},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			// Create request
+			handlerURL := fmt.Sprintf("/api/v1/species/%v", tc.ID)
+
+			// Do request
+			response := DoRequest(http.MethodGet, handlerURL, nil, "")
+
+			// Assert response
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+			assert.JSONEq(t, tc.ExpectedResponseBody, response.StringBody())
+		})
+	}
+}
+
+func TestGetSpeciesListHandler(t *testing.T) {
+
+	type TestCase struct {
+		Name                        string
+		ExpectedResponseBody        string
+		ExpectedMockSuccessResponse models.SpeciesList
+		ExpectedMockErrorResponse   error
+		ExpectedMockCallCount       int
+		ExpectedStatusCode          int
+	}
+
+	testCases := []TestCase{
+		{
+			Name: "Success",
+			ExpectedMockSuccessResponse: models.SpeciesList{
+				Count: 1,
+				Results: []models.Species{
+					{
+						Name:            "Wookie",
+						Classification:  "mammal",
+						Designation:     "sentient",
+						AverageHeight:   "210",
+						AverageLifespan: "400",
+						EyeColors:       "blue, green, yellow, brown, golden, red",
+						HairColors:      "black, brown",
+						SkinColors:      "gray",
+						Language:        "Shyriiwook",
+						Homeworld:       "https://swapi.dev/api/planets/14/",
+					},
+				},
+			},
+			ExpectedResponseBody:  `{"count":1,"results":[{"name":"Wookie","classification":"mammal","designation":"sentient","average_height":"210","average_lifespan":"400","eye_colors":"blue, green, yellow, brown, golden, red","hair_colors":"black, brown","skin_colors":"gray","language":"Shyriiwook","homeworld":"https://swapi.dev/api/planets/14/","people":null,"films":null}]}`,
+			ExpectedMockCallCount: 1,
+			ExpectedStatusCode:    http.StatusOK,
+		},
+		{
+			Name:                      "Not Found",
+			ExpectedMockErrorResponse: errors.NewNotFound("species", ""),
+			ExpectedResponseBody:      `{"type":"NOT_FOUND","code":"resource_not_found","message":"resource: species not found"}`,
+			ExpectedMockCallCount:     1,
+			ExpectedStatusCode:        http.StatusNotFound,
+		},
+		{
+			Name:                      "Internal Server Error",
+			ExpectedMockErrorResponse: errors.NewInternal(),
+			ExpectedResponseBody:      `{"type":"INTERNAL_SERVER_ERROR","code":"internal_error","message":"Internal server error."}`,
+			ExpectedMockCallCount:     1,
+			ExpectedStatusCode:        http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create client mock
+			swapiMock := swapi.MockClient{
+				GetSpeciesListFunc: func(ctx context.Context, page int) (models.SpeciesList, error) {
+					assert.Equal(t, 1, page)
+					return tc.ExpectedMockSuccessResponse, tc.ExpectedMockErrorResponse
+				},
+				GetSpeciesListFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedMockCallCount},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			// Create request
+			handlerURL := "/api/v1/species"
+
+			// Do request
+			response := DoRequest(http.MethodGet, handlerURL, nil, "")
+
+			// Assert response
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+			assert.JSONEq(t, tc.ExpectedResponseBody, response.StringBody())
+		})
+	}
+}
+
+func TestGetVehicleHandler(t *testing.T) {
+
+	type TestCase struct {
+		Name                        string
+		ID                          interface{}
+		ExpectedResponseBody        string
+		ExpectedStatusCode          int
+		ExpectedMockSuccessResponse models.Vehicle
+		ExpectedMockErrorResponse   error
+		ExpectedMockCallCount       int
+	}
+
+	testCases := []TestCase{
+		{
+			Name:               "Success",
+			ID:                 1,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMockSuccessResponse: models.Vehicle{
+				Name:                 "Sand Crawler",
+				Model:                "Digger Crawler",
+				Class:                "wheeled",
+				Manufacturer:         "Corellia Mining Corporation",
+				CostInCredits:        "150000",
+				Length:               "36.8",
+				Crew:                 "46",
+				Passengers:           "30",
+				MaxAtmospheringSpeed: "30",
+				CargoCapacity:        "50000",
+				Consumables:          "2 months",
+			},
+			ExpectedResponseBody:  `{"name":"Sand Crawler","model":"Digger Crawler","vehicle_class":"wheeled","manufacturer":"Corellia Mining Corporation","cost_in_credits":"150000","length":"36.8","crew":"46","passengers":"30","max_atmosphering_speed":"30","cargo_capacity":"50000","consumables":"2 months","films":null,"pilots":null}`,
+			ExpectedMockCallCount: 1,
+		},
+		{
+			Name:                 "Bad Request",
+			ID:                   "invalid_id",
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedResponseBody: `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid id"}`,
+		},
+		{
+			Name:                      "Not Found",
+			ID:                        1,
+			ExpectedStatusCode:        http.StatusNotFound,
+			ExpectedResponseBody:      `{"type":"NOT_FOUND","code":"resource_not_found","message":"resource: vehicles with id: 1 not found"}`,
+			ExpectedMockErrorResponse: errors.NewNotFound("vehicles", "1"),
+			ExpectedMockCallCount:     1,
+		},
+		{
+			Name:                      "Internal Server Error",
+			ID:                        1,
+			ExpectedStatusCode:        http.StatusInternalServerError,
+			ExpectedResponseBody:      `{"type":"INTERNAL_SERVER_ERROR","code":"internal_error","message":"Internal server error."}`,
+			ExpectedMockErrorResponse: errors.NewInternal(),
+			ExpectedMockCallCount:     1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create client mock
+			swapiMock := swapi.MockClient{
+				GetVehicleFunc: func(ctx context.Context, id int) (models.Vehicle, error) {
+					assert.Equal(t, tc.ID, id)
+
+					return tc.ExpectedMockSuccessResponse, tc.ExpectedMockErrorResponse
+				},
+				GetVehicleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedMockCallCount},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			// Create request
+			handlerURL := fmt.Sprintf("/api/v1/vehicles/%v", tc.ID)
+
+			// Do request
+			response := DoRequest(http.MethodGet, handlerURL, nil, "")
+
+			// Assert response
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+			assert.JSONEq(t, tc.ExpectedResponseBody, response.StringBody())
+		})
+	}
+}
+
+func TestGetVehiclesHandler(t *testing.T) {
+
+	type TestCase struct {
+		Name                        string
+		ExpectedResponseBody        string
+		ExpectedMockSuccessResponse models.Vehicles
+		ExpectedMockErrorResponse   error
+		ExpectedMockCallCount       int
+		ExpectedStatusCode          int
+	}
+
+	testCases := []TestCase{
+		{
+			Name: "Success",
+			ExpectedMockSuccessResponse: models.Vehicles{
+				Count: 1,
+				Results: []models.Vehicle{
+					{
+						Name:                 "Sand Crawler",
+						Model:                "Digger Crawler",
+						Class:                "wheeled",
+						Manufacturer:         "Corellia Mining Corporation",
+						CostInCredits:        "150000",
+						Length:               "36.8",
+						Crew:                 "46",
+						Passengers:           "30",
+						MaxAtmospheringSpeed: "30",
+						CargoCapacity:        "50000",
+						Consumables:          "2 months",
+					},
+				},
+			},
+			ExpectedResponseBody:  `{"count":1,"results":[{"name":"Sand Crawler","model":"Digger Crawler","vehicle_class":"wheeled","manufacturer":"Corellia Mining Corporation","cost_in_credits":"150000","length":"36.8","crew":"46","passengers":"30","max_atmosphering_speed":"30","cargo_capacity":"50000","consumables":"2 months","films":null,"pilots":null}]}`,
+			ExpectedMockCallCount: 1,
+			ExpectedStatusCode:    http.StatusOK,
+		},
+		{
+			Name:                      "Not Found",
+			ExpectedMockErrorResponse: errors.NewNotFound("vehicles", ""),
+			ExpectedResponseBody:      `{"type":"NOT_FOUND","code":"resource_not_found","message":"resource: vehicles not found"}`,
+			ExpectedMockCallCount:     1,
+			ExpectedStatusCode:        http.StatusNotFound,
+		},
+		{
+			Name:                      "Internal Server Error",
+			ExpectedMockErrorResponse: errors.NewInternal(),
+			ExpectedResponseBody:      `{"type":"INTERNAL_SERVER_ERROR","code":"internal_error","message":"Internal server error."}`,
+			ExpectedMockCallCount:     1,
+			ExpectedStatusCode:        http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create client mock
+			swapiMock := swapi.MockClient{
+				GetVehiclesFunc: func(ctx context.Context, page int) (models.Vehicles, error) {
+					assert.Equal(t, 1, page)
+					return tc.ExpectedMockSuccessResponse, tc.ExpectedMockErrorResponse
+				},
+				GetVehiclesFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedMockCallCount},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			// Create request
+			handlerURL := "/api/v1/vehicles"
+
+			// Do request
+			response := DoRequest(http.MethodGet, handlerURL, nil, "")
+
+			// Assert response
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+			assert.JSONEq(t, tc.ExpectedResponseBody, response.StringBody())
+		})
+	}
+}
+
+func TestGetAllStarships(t *testing.T) {
+
+	type TestCase struct {
+		Name                        string
+		Query                       string
+		ExpectedResponseBody        string
+		ExpectedMockSuccessResponse models.Starships
+		ExpectedMockErrorResponse   error
+		ExpectedMockCallCount       int
+		ExpectedStatusCode          int
+	}
+
+	testCases := []TestCase{
+		{
+			Name:  "Success",
+			Query: "all=true",
+			ExpectedMockSuccessResponse: models.Starships{
+				Count: 2,
+				Results: []models.Starship{
+					{Name: "CR90 corvette"},
+					{Name: "Star Destroyer"},
+				},
+			},
+			ExpectedResponseBody:  `{"count":2,"results":[{"name":"CR90 corvette","model":"","starship_class":"","manufacturer":"","cost_in_credits":"","length":"","crew":"","passengers":"","max_atmosphering_speed":"","hyperdrive_rating":"","MGLT":"","cargo_capacity":"","consumables":"","films":null,"pilots":null},{"name":"Star Destroyer","model":"","starship_class":"","manufacturer":"","cost_in_credits":"","length":"","crew":"","passengers":"","max_atmosphering_speed":"","hyperdrive_rating":"","MGLT":"","cargo_capacity":"","consumables":"","films":null,"pilots":null}]}`,
+			ExpectedMockCallCount: 1,
+			ExpectedStatusCode:    http.StatusOK,
+		},
+		{
+			Name:                 "Bad Request",
+			Query:                "all=maybe",
+			ExpectedResponseBody: `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid all"}`,
+			ExpectedStatusCode:   http.StatusBadRequest,
+		},
+		{
+			Name:                      "Internal Server Error",
+			Query:                     "all=true",
+			ExpectedMockErrorResponse: errors.NewInternal(),
+			ExpectedResponseBody:      `{"type":"INTERNAL_SERVER_ERROR","code":"internal_error","message":"Internal server error."}`,
+			ExpectedMockCallCount:     1,
+			ExpectedStatusCode:        http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create client mock
+			swapiMock := swapi.MockClient{
+				GetAllStarshipsFunc: func(ctx context.Context) (models.Starships, error) {
+					return tc.ExpectedMockSuccessResponse, tc.ExpectedMockErrorResponse
+				},
+				GetAllStarshipsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedMockCallCount},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			// Create request
+			handlerURL := "/api/v1/starships?" + tc.Query
+
+			// Do request
+			response := DoRequest(http.MethodGet, handlerURL, nil, "")
+
+			// Assert response
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+			assert.JSONEq(t, tc.ExpectedResponseBody, response.StringBody())
+		})
+	}
+}
+
+func TestGetAllPeople(t *testing.T) {
+
+	type TestCase struct {
+		Name                        string
+		Query                       string
+		ExpectedResponseBody        string
+		ExpectedMockSuccessResponse models.PeopleList
+		ExpectedMockErrorResponse   error
+		ExpectedMockCallCount       int
+		ExpectedStatusCode          int
+	}
+
+	testCases := []TestCase{
+		{
+			Name:  "Success",
+			Query: "all=true",
+			ExpectedMockSuccessResponse: models.PeopleList{
+				Count: 2,
+				Results: []models.People{
+					{Name: "Luke Skywalker"},
+					{Name: "C-3PO"},
+				},
+			},
+			ExpectedResponseBody:  `{"count":2,"results":[{"name":"Luke Skywalker","birth_year":"","eye_color":"","gender":"","hair_color":"","height":"","mass":"","skin_color":"","homeworld":"","films":null,"species":null,"starships":null},{"name":"C-3PO","birth_year":"","eye_color":"","gender":"","hair_color":"","height":"","mass":"","skin_color":"","homeworld":"","films":null,"species":null,"starships":null}]}`,
+			ExpectedMockCallCount: 1,
+			ExpectedStatusCode:    http.StatusOK,
+		},
+		{
+			Name:                 "Bad Request",
+			Query:                "all=maybe",
+			ExpectedResponseBody: `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid all"}`,
+			ExpectedStatusCode:   http.StatusBadRequest,
+		},
+		{
+			Name:                      "Not Found",
+			Query:                     "all=true",
+			ExpectedMockErrorResponse: errors.NewNotFound("people", ""),
+			ExpectedResponseBody:      `{"type":"NOT_FOUND","code":"resource_not_found","message":"resource: people not found"}`,
+			ExpectedMockCallCount:     1,
+			ExpectedStatusCode:        http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create client mock
+			swapiMock := swapi.MockClient{
+				GetAllPeopleFunc: func(ctx context.Context) (models.PeopleList, error) {
+					return tc.ExpectedMockSuccessResponse, tc.ExpectedMockErrorResponse
+				},
+				GetAllPeopleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedMockCallCount},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			// Create request
+			handlerURL := "/api/v1/people?" + tc.Query
+
+			// Do request
+			response := DoRequest(http.MethodGet, handlerURL, nil, "")
+
+			// Assert response
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+			assert.JSONEq(t, tc.ExpectedResponseBody, response.StringBody())
+		})
+	}
+}
+
+func TestGetStarshipsPagination(t *testing.T) {
+
+	type TestCase struct {
+		Name                  string
+		Query                 string
+		ExpectedPage          int
+		ExpectedPageCalls     int
+		ExpectedAllCalls      int
+		ExpectedStatusCode    int
+		ExpectedNames         []string
+		ExpectedNext          string
+		ExpectedPrevious      string
+		ExpectedResponseBody  string
+		UpstreamPageResponses models.Starships
+	}
+
+	starships := func(from, to int) []models.Starship {
+		result := []models.Starship{}
+
+		for i := from; i <= to; i++ {
+			result = append(result, models.Starship{Name: fmt.Sprintf("starship %d", i)})
+		}
+
+		return result
+	}
+
+	names := func(from, to int) []string {
+		result := []string{}
+
+		for i := from; i <= to; i++ {
+			result = append(result, fmt.Sprintf("starship %d", i))
+		}
+
+		return result
+	}
+
+	testCases := []TestCase{
+		{
+			Name:               "Upstream page",
+			Query:              "page=2",
+			ExpectedPage:       2,
+			ExpectedPageCalls:  1,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedNames:      names(11, 20),
+			ExpectedNext:       "http://example.com/api/v1/starships?page=3",
+			ExpectedPrevious:   "http://example.com/api/v1/starships?page=1",
+		},
+		{
+			Name:               "Last upstream page",
+			Query:              "page=4",
+			ExpectedPage:       4,
+			ExpectedPageCalls:  1,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedNames:      names(31, 36),
+			ExpectedPrevious:   "http://example.com/api/v1/starships?page=3",
+		},
+		{
+			Name:               "Custom page size",
+			Query:              "page=2&page_size=5",
+			ExpectedAllCalls:   1,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedNames:      names(6, 10),
+			ExpectedNext:       "http://example.com/api/v1/starships?page=3&page_size=5",
+			ExpectedPrevious:   "http://example.com/api/v1/starships?page=1&page_size=5",
+		},
+		{
+			Name:                 "Custom page size out of range",
+			Query:                "page=100&page_size=20",
+			ExpectedAllCalls:     1,
+			ExpectedStatusCode:   http.StatusNotFound,
+			ExpectedResponseBody: `{"type":"NOT_FOUND","code":"resource_not_found","message":"resource: starships not found"}`,
+		},
+		{
+			Name:                 "Invalid page",
+			Query:                "page=zero",
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedResponseBody: `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid page"}`,
+		},
+		{
+			Name:                 "Negative page",
+			Query:                "page=-1",
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedResponseBody: `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid page"}`,
+		},
+		{
+			Name:                 "Invalid page size",
+			Query:                "page_size=0",
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedResponseBody: `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid page_size"}`,
+		},
+		{
+			Name:                 "Page size too big",
+			Query:                "page_size=1000",
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedResponseBody: `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid page_size"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create client mock
+			swapiMock := swapi.MockClient{
+				GetStarshipsFunc: func(ctx context.Context, page int) (models.Starships, error) {
+					assert.Equal(t, tc.ExpectedPage, page)
+
+					from := (page-1)*swapi.PageSize + 1
+					to := from + swapi.PageSize - 1
+
+					if to > 36 {
+						to = 36
+					}
+
+					return models.Starships{
+						Count:   36,
+						Next:    "https://swapi.dev/api/starships/?page=99",
+						Results: starships(from, to),
+					}, nil
+				},
+				GetStarshipsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedPageCalls},
+				GetAllStarshipsFunc: func(ctx context.Context) (models.Starships, error) {
+					return models.Starships{Count: 36, Results: starships(1, 36)}, nil
+				},
+				GetAllStarshipsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedAllCalls},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			// Create request
+			handlerURL := "/api/v1/starships?" + tc.Query
+
+			// Do request
+			response := DoRequest(http.MethodGet, handlerURL, nil, "")
+
+			// Assert response
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+
+			if tc.ExpectedStatusCode != http.StatusOK {
+				assert.JSONEq(t, tc.ExpectedResponseBody, response.StringBody())
+				return
+			}
+
+			var result models.Starships
+
+			assert.NoError(t, json.Unmarshal(response.Body, &result))
+			assert.Equal(t, 36, result.Count)
+			assert.Equal(t, tc.ExpectedNext, result.Next)
+			assert.Equal(t, tc.ExpectedPrevious, result.Previous)
+
+			resultNames := []string{}
+
+			for _, starship := range result.Results {
+				resultNames = append(resultNames, starship.Name)
+			}
+
+			assert.Equal(t, tc.ExpectedNames, resultNames)
+		})
+	}
+}
+
+func TestGetPeopleListPagination(t *testing.T) {
+	people := []models.People{}
+
+	for i := 1; i <= 12; i++ {
+		people = append(people, models.People{Name: fmt.Sprintf("person %d", i)})
+	}
+
+	swapiMock := swapi.MockClient{
+		GetAllPeopleFunc: func(ctx context.Context) (models.PeopleList, error) {
+			return models.PeopleList{Count: len(people), Results: people}, nil
+		},
+		GetAllPeopleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	swapiMock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+	response := DoRequest(http.MethodGet, "/api/v1/people?page=3&page_size=5", nil, "")
+
+	var result models.PeopleList
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NoError(t, json.Unmarshal(response.Body, &result))
+	assert.Equal(t, 12, result.Count)
+	assert.Equal(t, "", result.Next)
+	assert.Equal(t, "http://example.com/api/v1/people?page=2&page_size=5", result.Previous)
+	assert.Equal(t, people[10:], result.Results)
+}
+
+func TestSearchStarships(t *testing.T) {
+
+	type TestCase struct {
+		Name                        string
+		Query                       string
+		ExpectedResponseBody        string
+		ExpectedMockSuccessResponse models.Starships
+		ExpectedMockErrorResponse   error
+		ExpectedMockCallCount       int
+		ExpectedStatusCode          int
+	}
+
+	testCases := []TestCase{
+		{
+			Name:  "Success",
+			Query: "search=death",
+			ExpectedMockSuccessResponse: models.Starships{
+				Count:   1,
+				Results: []models.Starship{{Name: "Death Star"}},
+			},
+			ExpectedResponseBody:  `{"count":1,"results":[{"name":"Death Star","model":"","starship_class":"","manufacturer":"","cost_in_credits":"","length":"","crew":"","passengers":"","max_atmosphering_speed":"","hyperdrive_rating":"","MGLT":"","cargo_capacity":"","consumables":"","films":null,"pilots":null}]}`,
+			ExpectedMockCallCount: 1,
+			ExpectedStatusCode:    http.StatusOK,
+		},
+		{
+			Name:  "No matches",
+			Query: "search=death",
+			ExpectedMockSuccessResponse: models.Starships{
+				Results: []models.Starship{},
+			},
+			ExpectedResponseBody:  `{"count":0,"results":[]}`,
+			ExpectedMockCallCount: 1,
+			ExpectedStatusCode:    http.StatusOK,
+		},
+		{
+			Name:                      "Internal Server Error",
+			Query:                     "search=death",
+			ExpectedMockErrorResponse: errors.NewInternal(),
+			ExpectedResponseBody:      `{"type":"INTERNAL_SERVER_ERROR","code":"internal_error","message":"Internal server error."}`,
+			ExpectedMockCallCount:     1,
+			ExpectedStatusCode:        http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create client mock
+			swapiMock := swapi.MockClient{
+				SearchStarshipsFunc: func(ctx context.Context, query string) (models.Starships, error) {
+					assert.Equal(t, "death", query)
+					return tc.ExpectedMockSuccessResponse, tc.ExpectedMockErrorResponse
+				},
+				SearchStarshipsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedMockCallCount},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			// Create request
+			handlerURL := "/api/v1/starships?" + tc.Query
+
+			// Do request
+			response := DoRequest(http.MethodGet, handlerURL, nil, "")
+
+			// Assert response
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+			assert.JSONEq(t, tc.ExpectedResponseBody, response.StringBody())
+		})
+	}
+}
+
+func TestSearchPeople(t *testing.T) {
+	people := []models.People{}
+
+	for i := 1; i <= 12; i++ {
+		people = append(people, models.People{Name: fmt.Sprintf("Skywalker %d", i)})
+	}
+
+	type TestCase struct {
+		Name             string
+		Query            string
+		ExpectedResults  []models.People
+		ExpectedNext     string
+		ExpectedPrevious string
+	}
+
+	testCases := []TestCase{
+		{
+			Name:            "First page",
+			Query:           "search=skywalker",
+			ExpectedResults: people[:10],
+			ExpectedNext:    "http://example.com/api/v1/people?page=2&search=skywalker",
+		},
+		{
+			Name:             "Second page",
+			Query:            "search=skywalker&page=2&page_size=5",
+			ExpectedResults:  people[5:10],
+			ExpectedNext:     "http://example.com/api/v1/people?page=3&page_size=5&search=skywalker",
+			ExpectedPrevious: "http://example.com/api/v1/people?page=1&page_size=5&search=skywalker",
+		},
+		{
+			Name:            "All",
+			Query:           "search=skywalker&all=true",
+			ExpectedResults: people,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create client mock
+			swapiMock := swapi.MockClient{
+				SearchPeopleFunc: func(ctx context.Context, query string) (models.PeopleList, error) {
+					assert.Equal(t, "skywalker", query)
+					return models.PeopleList{Count: len(people), Results: people}, nil
+				},
+				SearchPeopleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			// Do request
+			response := DoRequest(http.MethodGet, "/api/v1/people?"+tc.Query, nil, "")
+
+			var result models.PeopleList
+
+			// Assert response
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			assert.NoError(t, json.Unmarshal(response.Body, &result))
+			assert.Equal(t, len(people), result.Count)
+			assert.Equal(t, tc.ExpectedResults, result.Results)
+			assert.Equal(t, tc.ExpectedNext, result.Next)
+			assert.Equal(t, tc.ExpectedPrevious, result.Previous)
+		})
+	}
+}
+
+func TestHandlersPropagateRequestContext(t *testing.T) {
+	type ctxKey struct{}
+
+	swapiMock := swapi.MockClient{
+		GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+			assert.Equal(t, "from request", ctx.Value(ctxKey{}))
+			return models.People{}, nil
+		},
+		GetPeopleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	swapiMock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/people/1", nil)
+	request = request.WithContext(context.WithValue(request.Context(), ctxKey{}, "from request"))
+
+	response := httptest.NewRecorder()
+
+	GetTestRouter().ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+}
+
+func TestGetDiagnosticsHandler(t *testing.T) {
+	t.Run("Client without diagnostics", func(t *testing.T) {
+		swapiMock := swapi.MockClient{}
+
+		swapiMock.Use()
+		defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+		response := DoRequest(http.MethodGet, "/api/v1/diagnostics", nil, "")
+
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.JSONEq(t, `{}`, response.StringBody())
+	})
+
+	t.Run("Cached client", func(t *testing.T) {
+		swapiMock := swapi.MockClient{
+			GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+				return models.People{}, nil
+			},
+			GetPeopleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+		}
+
+		swapiMock.Use()
+		defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+		swapi.Instance = swapi.NewCachedClient(&swapiMock, swapi.DefaultCacheOptions())
+		defer swapi.Close(swapi.Instance)
+
+		DoRequest(http.MethodGet, "/api/v1/people/1", nil, "")
+		DoRequest(http.MethodGet, "/api/v1/people/1", nil, "")
+
+		response := DoRequest(http.MethodGet, "/api/v1/diagnostics", nil, "")
+
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.JSONEq(t, `{"cache":{"hits":1,"misses":1,"entries":1}}`, response.StringBody())
+	})
+}
+
+func TestTypedFormat(t *testing.T) {
+	starship := models.Starship{
+		Name:                 "X-wing",
+		CostInCredits:        "149999",
+		Length:               "12.5",
+		Crew:                 "1",
+		Passengers:           "0",
+		MaxAtmospheringSpeed: "1050",
+		HyperdriveRating:     "1.0",
+		MGLT:                 "100",
+		CargoCapacity:        "110",
+		Consumables:          "1 week",
+	}
+
+	people := models.People{Name: "Luke Skywalker", BirthYear: "19BBY", Height: "172", Mass: "unknown"}
+
+	typedStarship := `{"name":"X-wing","model":"","starship_class":"","manufacturer":"","cost_in_credits":149999,"length":12.5,"crew":{"min":1,"max":1},"passengers":0,"max_atmosphering_speed":1050,"hyperdrive_rating":1,"MGLT":100,"cargo_capacity":110,"consumables_days":7,"films":null,"pilots":null}`
+	typedPeople := `{"name":"Luke Skywalker","birth_year":"19BBY","birth_year_bby":19,"eye_color":"","gender":"","hair_color":"","height":172,"mass":null,"skin_color":"","homeworld":"","films":null,"species":null,"starships":null}`
+
+	type TestCase struct {
+		Name                 string
+		URL                  string
+		ExpectedStatusCode   int
+		ExpectedResponseBody string
+	}
+
+	testCases := []TestCase{
+		{
+			Name:                 "Starship",
+			URL:                  "/api/v1/starships/12?format=typed",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: typedStarship,
+		},
+		{
+			Name:                 "Starships",
+			URL:                  "/api/v1/starships?format=typed",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"count":1,"results":[` + typedStarship + `]}`,
+		},
+		{
+			Name:                 "People",
+			URL:                  "/api/v1/people/1?format=typed",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: typedPeople,
+		},
+		{
+			Name:                 "People list",
+			URL:                  "/api/v1/people?format=typed",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"count":1,"results":[` + typedPeople + `]}`,
+		},
+		{
+			Name:                 "Raw",
+			URL:                  "/api/v1/people/1?format=raw",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"name":"Luke Skywalker","birth_year":"19BBY","eye_color":"","gender":"","hair_color":"","height":"172","mass":"unknown","skin_color":"","homeworld":"","films":null,"species":null,"starships":null}`,
+		},
+		{
+			Name:                 "Invalid format",
+			URL:                  "/api/v1/starships/12?format=xml",
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedResponseBody: `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid format"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			swapiMock := swapi.MockClient{
+				GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
+					return starship, nil
+				},
+				GetStarshipsFunc: func(ctx context.Context, page int) (models.Starships, error) {
+					return models.Starships{Count: 1, Results: []models.Starship{starship}}, nil
+				},
+				GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+					return people, nil
+				},
+				GetPeopleListFunc: func(ctx context.Context, page int) (models.PeopleList, error) {
+					return models.PeopleList{Count: 1, Results: []models.People{people}}, nil
+				},
+			}
+
+			swapiMock.Use()
+			defer swapiMock.CleanUp()
+
+			response := DoRequest(http.MethodGet, tc.URL, nil, "")
+
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+			assert.JSONEq(t, tc.ExpectedResponseBody, response.StringBody())
+		})
+	}
+}
+
+func TestLinks(t *testing.T) {
+	luke := models.People{
+		ID:        1,
+		Name:      "Luke Skywalker",
+		Homeworld: "https://swapi.dev/api/planets/1/",
+		Films:     []string{"https://swapi.dev/api/films/1/", "https://swapi.dev/api/films/2/"},
+		Species:   []string{},
+		Starships: []string{"https://swapi.dev/api/starships/12/"},
+		URL:       "https://swapi.dev/api/people/1/",
+	}
+
+	type TestCase struct {
+		Name                 string
+		URL                  string
+		ExpectedStatusCode   int
+		ExpectedResponseBody string
+	}
+
+	testCases := []TestCase{
+		{
+			Name:                 "SWAPI links by default",
+			URL:                  "/api/v1/people/1",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"id":1,"name":"Luke Skywalker","birth_year":"","eye_color":"","gender":"","hair_color":"","height":"","mass":"","skin_color":"","homeworld":"https://swapi.dev/api/planets/1/","films":["https://swapi.dev/api/films/1/","https://swapi.dev/api/films/2/"],"species":[],"starships":["https://swapi.dev/api/starships/12/"],"url":"https://swapi.dev/api/people/1/"}`,
+		},
+		{
+			Name:                 "Bare ids",
+			URL:                  "/api/v1/people/1?links=ids",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"id":1,"name":"Luke Skywalker","birth_year":"","eye_color":"","gender":"","hair_color":"","height":"","mass":"","skin_color":"","homeworld":1,"films":[1,2],"species":[],"starships":[12],"url":1}`,
+		},
+		{
+			Name:                 "API links",
+			URL:                  "/api/v1/people/1?links=api",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"id":1,"name":"Luke Skywalker","birth_year":"","eye_color":"","gender":"","hair_color":"","height":"","mass":"","skin_color":"","homeworld":"http://example.com/api/v1/planets/1","films":["http://example.com/api/v1/films/1","http://example.com/api/v1/films/2"],"species":[],"starships":["http://example.com/api/v1/starships/12"],"url":"http://example.com/api/v1/people/1"}`,
+		},
+		{
+			Name:                 "Lists keep page links",
+			URL:                  "/api/v1/people?links=ids&page_size=1",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"count":2,"next":"http://example.com/api/v1/people?links=ids&page=2&page_size=1","results":[{"id":1,"name":"Luke Skywalker","birth_year":"","eye_color":"","gender":"","hair_color":"","height":"","mass":"","skin_color":"","homeworld":1,"films":[1,2],"species":[],"starships":[12],"url":1}]}`,
+		},
+		{
+			Name:                 "Typed",
+			URL:                  "/api/v1/people/1?links=ids&format=typed",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"id":1,"name":"Luke Skywalker","birth_year":"","birth_year_bby":null,"eye_color":"","gender":"","hair_color":"","height":null,"mass":null,"skin_color":"","homeworld":1,"films":[1,2],"species":[],"starships":[12],"url":1}`,
+		},
+		{
+			Name:                 "Invalid links",
+			URL:                  "/api/v1/people/1?links=all",
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedResponseBody: `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid links"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			swapiMock := swapi.MockClient{
+				GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+					return luke, nil
+				},
+				GetAllPeopleFunc: func(ctx context.Context) (models.PeopleList, error) {
+					return models.PeopleList{Count: 2, Results: []models.People{luke, {ID: 2}}}, nil
+				},
+			}
+
+			swapiMock.Use()
+			defer swapiMock.CleanUp()
+
+			response := DoRequest(http.MethodGet, tc.URL, nil, "")
+
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+			assert.JSONEq(t, tc.ExpectedResponseBody, response.StringBody())
+		})
+	}
+}
+
+func TestExpand(t *testing.T) {
+	luke := models.People{
+		ID:        1,
+		Name:      "Luke Skywalker",
+		Homeworld: "https://swapi.dev/api/planets/1/",
+		Films:     []string{"https://swapi.dev/api/films/1/", "https://swapi.dev/api/films/7/"},
+	}
+
+	xwing := models.Starship{
+		ID:     12,
+		Name:   "X-wing",
+		Pilots: []string{"https://swapi.dev/api/people/1/"},
+	}
+
+	type TestCase struct {
+		Name                 string
+		URL                  string
+		ExpectedStatusCode   int
+		ExpectedResponseBody string
+	}
+
+	testCases := []TestCase{
+		{
+			Name:                 "People",
+			URL:                  "/api/v1/people/1?expand=homeworld,films",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"id":1,"name":"Luke Skywalker","birth_year":"","eye_color":"","gender":"","hair_color":"","height":"","mass":"","skin_color":"","homeworld":{"id":1,"name":"Tatooine","rotation_period":"","orbital_period":"","diameter":"","climate":"","gravity":"","terrain":"","surface_water":"","population":"","residents":null,"films":null},"films":[{"id":1,"title":"A New Hope","episode_id":4,"opening_crawl":"","director":"","producer":"","release_date":"","characters":null,"planets":null,"starships":null,"vehicles":null,"species":null},"https://swapi.dev/api/films/7/"],"species":null,"starships":null,"expand_errors":[{"field":"films","link":"https://swapi.dev/api/films/7/","error":{"type":"NOT_FOUND","code":"resource_not_found","message":"resource: films with id: 7 not found"}}]}`,
+		},
+		{
+			Name:                 "Starship with bare ids",
+			URL:                  "/api/v1/starships/12?expand=pilots&links=ids",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"id":12,"name":"X-wing","model":"","starship_class":"","manufacturer":"","cost_in_credits":"","length":"","crew":"","passengers":"","max_atmosphering_speed":"","hyperdrive_rating":"","MGLT":"","cargo_capacity":"","consumables":"","films":null,"pilots":[{"id":1,"name":"Luke Skywalker","birth_year":"","eye_color":"","gender":"","hair_color":"","height":"","mass":"","skin_color":"","homeworld":1,"films":[1,7],"species":null,"starships":null}]}`,
+		},
+		{
+			Name:                 "Invalid expand",
+			URL:                  "/api/v1/starships/12?expand=pilots,crew",
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedResponseBody: `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid expand \"crew\", valid values are: films, pilots"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			swapiMock := swapi.MockClient{
+				GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+					return luke, nil
+				},
+				GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
+					return xwing, nil
+				},
+				GetPlanetFunc: func(ctx context.Context, id int) (models.Planet, error) {
+					return models.Planet{ID: 1, Name: "Tatooine"}, nil
+				},
+				GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+					if id == 7 {
+						return models.Film{}, errors.NewNotFound("films", "7")
+					}
+
+					return models.Film{ID: 1, Title: "A New Hope", EpisodeID: 4}, nil
+				},
+			}
+
+			swapiMock.Use()
+			defer swapiMock.CleanUp()
+
+			response := DoRequest(http.MethodGet, tc.URL, nil, "")
+
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+			assert.JSONEq(t, tc.ExpectedResponseBody, response.StringBody())
+		})
+	}
+}
+
+func TestFields(t *testing.T) {
+	starship := models.Starship{ID: 12, Name: "X-wing", Model: "T-65 X-wing", Crew: "1", Consumables: "1 week"}
+
+	type TestCase struct {
+		Name                 string
+		URL                  string
+		ExpectedStatusCode   int
+		ExpectedResponseBody string
+	}
+
+	testCases := []TestCase{
+		{
+			Name:                 "Single resource",
+			URL:                  "/api/v1/starships/12?fields=name,model,crew",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"name":"X-wing","model":"T-65 X-wing","crew":"1"}`,
+		},
+		{
+			Name:                 "List results",
+			URL:                  "/api/v1/starships?fields=id,name",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"count":1,"results":[{"id":12,"name":"X-wing"}]}`,
+		},
+		{
+			Name:                 "Typed",
+			URL:                  "/api/v1/starships/12?format=typed&fields=crew,consumables_days",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"crew":{"min":1,"max":1},"consumables_days":7}`,
+		},
+		{
+			Name:                 "Other resources",
+			URL:                  "/api/v1/films/1?fields=title,episode_id",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"title":"A New Hope","episode_id":4}`,
+		},
+		{
+			Name:                 "Unknown field",
+			URL:                  "/api/v1/starships?fields=name,consumables_days",
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedResponseBody: `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid fields \"consumables_days\", valid values are: id, name, model, starship_class, manufacturer, cost_in_credits, length, crew, passengers, max_atmosphering_speed, hyperdrive_rating, MGLT, cargo_capacity, consumables, films, pilots, url"}`,
+		},
+		{
+			Name:                 "Unknown field of another resource",
+			URL:                  "/api/v1/planets/1?fields=crew",
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedResponseBody: `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid fields \"crew\", valid values are: id, name, rotation_period, orbital_period, diameter, climate, gravity, terrain, surface_water, population, residents, films, url"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			swapiMock := swapi.MockClient{
+				GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
+					return starship, nil
+				},
+				GetStarshipsFunc: func(ctx context.Context, page int) (models.Starships, error) {
+					return models.Starships{Count: 1, Results: []models.Starship{starship}}, nil
+				},
+				GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+					return models.Film{ID: 1, Title: "A New Hope", EpisodeID: 4}, nil
+				},
+			}
+
+			swapiMock.Use()
+			defer swapiMock.CleanUp()
+
+			response := DoRequest(http.MethodGet, tc.URL, nil, "")
+
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+			assert.JSONEq(t, tc.ExpectedResponseBody, response.StringBody())
+		})
+	}
+}
+
+func TestFilterAndSort(t *testing.T) {
+	people := []models.People{
+		{Name: "Luke Skywalker", Gender: "male"},
+		{Name: "Shmi Skywalker", Gender: "female"},
+		{Name: "Leia Organa", Gender: "female"},
+		{Name: "Beru Whitesun lars", Gender: "female"},
+	}
+
+	starships := []models.Starship{
+		{Name: "X-wing", Class: "Starfighter", Length: "12.5", HyperdriveRating: "1.0"},
+		{Name: "TIE Advanced x1", Class: "Starfighter", Length: "9.2", HyperdriveRating: "1.0"},
+		{Name: "Death Star", Class: "Deep Space Mobile Battlestation", Length: "120000", HyperdriveRating: "4.0"},
+	}
+
+	type TestCase struct {
+		Name                 string
+		URL                  string
+		ExpectedStatusCode   int
+		ExpectedResponseBody string
+	}
+
+	testCases := []TestCase{
+		{
+			Name:                 "People",
+			URL:                  "/api/v1/people?gender=female&sort=name&page_size=2&fields=name",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"count":3,"next":"http://example.com/api/v1/people?fields=name&gender=female&page=2&page_size=2&sort=name","results":[{"name":"Beru Whitesun lars"},{"name":"Leia Organa"}]}`,
+		},
+		{
+			Name:                 "All people",
+			URL:                  "/api/v1/people?gender_ne=female&all=true&fields=name",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"count":1,"results":[{"name":"Luke Skywalker"}]}`,
+		},
+		{
+			Name:                 "Starships",
+			URL:                  "/api/v1/starships?starship_class=Starfighter&hyperdrive_rating_lt=2&sort=-length&fields=name",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"count":2,"results":[{"name":"X-wing"},{"name":"TIE Advanced x1"}]}`,
+		},
+		{
+			Name:                 "Invalid sort",
+			URL:                  "/api/v1/people?sort=-age",
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedResponseBody: `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid sort \"age\", valid fields are: birth_year, eye_color, gender, hair_color, height, mass, name, skin_color"}`,
+		},
+		{
+			Name:                 "Unknown filter",
+			URL:                  "/api/v1/starships?hyperdrive_ratng_lt=2&page_size=5&pretty=false",
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedResponseBody: `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid filter \"hyperdrive_ratng\", valid fields are: MGLT, cargo_capacity, consumables, cost_in_credits, crew, hyperdrive_rating, length, manufacturer, max_atmosphering_speed, model, name, passengers, starship_class"}`,
+		},
+		{
+			Name:                 "Invalid number",
+			URL:                  "/api/v1/starships?hyperdrive_rating_lt=fast",
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedResponseBody: `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid hyperdrive_rating \"fast\", it must be a number"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			swapiMock := swapi.MockClient{
+				GetAllPeopleFunc: func(ctx context.Context) (models.PeopleList, error) {
+					return models.PeopleList{Count: len(people), Results: people}, nil
+				},
+				GetAllStarshipsFunc: func(ctx context.Context) (models.Starships, error) {
+					return models.Starships{Count: len(starships), Results: starships}, nil
+				},
+			}
+
+			swapiMock.Use()
+			defer swapiMock.CleanUp()
+
+			response := DoRequest(http.MethodGet, tc.URL, nil, "")
+
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+			assert.JSONEq(t, tc.ExpectedResponseBody, response.StringBody())
+		})
+	}
+}
+
+func TestHealthHandlers(t *testing.T) {
+	var status int32 = http.StatusOK
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(int(atomic.LoadInt32(&status)))
+	}))
+	defer upstream.Close()
+
+	options := swapi.DefaultClientOptions()
+	options.BaseURL = upstream.URL
+	options.PingTTL = 0
+
+	swapi.Instance = swapi.NewCachedClient(swapi.NewCoalescingClient(swapi.NewSWAPIClient(options)), swapi.DefaultCacheOptions())
+	defer swapi.Close(swapi.Instance)
+
+	type TestCase struct {
+		Name                 string
+		URL                  string
+		Accept               string
+		UpstreamStatus       int32
+		ExpectedStatusCode   int
+		ExpectedResponseBody string
+	}
+
+	testCases := []TestCase{
+		{
+			Name:                 "Live",
+			URL:                  "/healthz",
+			UpstreamStatus:       http.StatusOK,
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			Name:                 "Live whatever the client accepts",
+			URL:                  "/healthz",
+			Accept:               "application/xml",
+			UpstreamStatus:       http.StatusOK,
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			Name:                 "Live with an invalid pretty",
+			URL:                  "/healthz?pretty=nope",
+			UpstreamStatus:       http.StatusOK,
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			Name:                 "Live while upstream fails",
+			URL:                  "/healthz",
+			UpstreamStatus:       http.StatusInternalServerError,
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			Name:                 "Ready",
+			URL:                  "/readyz",
+			UpstreamStatus:       http.StatusOK,
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponseBody: `{"status":"ok","checks":{"swapi":{"status":"ok","required":true}}}`,
+		},
+		{
+			Name:                 "Not ready",
+			URL:                  "/readyz",
+			UpstreamStatus:       http.StatusBadGateway,
+			ExpectedStatusCode:   http.StatusServiceUnavailable,
+			ExpectedResponseBody: `{"status":"failing","checks":{"swapi":{"status":"failing","required":true,"code":"upstream_error"}}}`,
+		},
+		{
+			Name:                 "Not ready whatever the client accepts",
+			URL:                  "/readyz",
+			Accept:               "application/xml",
+			UpstreamStatus:       http.StatusBadGateway,
+			ExpectedStatusCode:   http.StatusServiceUnavailable,
+			ExpectedResponseBody: `{"status":"failing","checks":{"swapi":{"status":"failing","required":true,"code":"upstream_error"}}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			atomic.StoreInt32(&status, tc.UpstreamStatus)
+
+			headers := http.Header{}
+
+			if tc.Accept != "" {
+				headers.Set("Accept", tc.Accept)
+			}
+
+			response := DoRequest(http.MethodGet, tc.URL, headers, "")
+
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+			assert.JSONEq(t, tc.ExpectedResponseBody, response.StringBody())
+		})
+	}
+
+	t.Run("Failing check is logged", func(t *testing.T) {
+		atomic.StoreInt32(&status, http.StatusBadGateway)
+
+		var logs bytes.Buffer
+
+		router := chi.NewRouter()
+		router.Use(RequestLogger(logging.New(&logs, logging.LevelDebug)))
+		URLMapping(router)
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		var entry map[string]interface{}
+		assert.Nil(t, json.Unmarshal(logs.Bytes(), &entry))
+
+		assert.Equal(t, "Bad gateway. The upstream server failed to handle the request.", entry["error"])
+		assert.Equal(t, "upstream responded with status 502", entry["cause"])
+	})
+}
+
+func TestProblemDetails(t *testing.T) {
+
+	type TestCase struct {
+		Name                 string
+		URL                  string
+		Accept               string
+		ProblemDetails       bool
+		ExpectedStatusCode   int
+		ExpectedContentType  string
+		ExpectedResponseBody string
+	}
+
+	notFound := `{"type":"urn:swapi:problem:not-found","title":"Not Found","status":404,"detail":"resource: people with id: 99 not found","instance":"/api/v1/people/99","code":"resource_not_found","resource":"people","id":"99"}`
+
+	testCases := []TestCase{
+		{
+			Name:                 "Plain JSON by default",
+			URL:                  "/api/v1/people/99",
+			ExpectedStatusCode:   http.StatusNotFound,
+			ExpectedContentType:  "application/json",
+			ExpectedResponseBody: `{"type":"NOT_FOUND","code":"resource_not_found","message":"resource: people with id: 99 not found"}`,
+		},
+		{
+			Name:                 "Accepted",
+			URL:                  "/api/v1/people/99",
+			Accept:               "application/problem+json",
+			ExpectedStatusCode:   http.StatusNotFound,
+			ExpectedContentType:  "application/problem+json",
+			ExpectedResponseBody: notFound,
+		},
+		{
+			Name:                 "JSON preferred",
+			URL:                  "/api/v1/people/99",
+			Accept:               "application/json, application/problem+json;q=0.1",
+			ExpectedStatusCode:   http.StatusNotFound,
+			ExpectedContentType:  "application/json",
+			ExpectedResponseBody: `{"type":"NOT_FOUND","code":"resource_not_found","message":"resource: people with id: 99 not found"}`,
+		},
+		{
+			Name:                 "Configured",
+			URL:                  "/api/v1/people/99",
+			ProblemDetails:       true,
+			ExpectedStatusCode:   http.StatusNotFound,
+			ExpectedContentType:  "application/problem+json",
+			ExpectedResponseBody: notFound,
+		},
+		{
+			Name:                 "Bad request",
+			URL:                  "/api/v1/people/luke",
+			Accept:               "application/problem+json",
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedContentType:  "application/problem+json",
+			ExpectedResponseBody: `{"type":"urn:swapi:problem:bad-request","title":"Bad Request","status":400,"detail":"Bad request. Reason: invalid id","instance":"/api/v1/people/luke","code":"invalid_request"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			swapiMock := swapi.MockClient{
+				GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+					return models.People{}, errors.NewNotFound("people", fmt.Sprint(id))
+				},
+			}
+
+			swapiMock.Use()
+			defer swapiMock.CleanUp()
+
+			httphelpers.ProblemDetails = tc.ProblemDetails
+			defer func() { httphelpers.ProblemDetails = false }()
+
+			headers := http.Header{}
+
+			if tc.Accept != "" {
+				headers.Set("Accept", tc.Accept)
+			}
+
+			response := DoRequest(http.MethodGet, tc.URL, headers, "")
+
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.ExpectedContentType, response.Headers.Get("Content-Type"))
+			assert.JSONEq(t, tc.ExpectedResponseBody, response.StringBody())
+		})
+	}
+}
+
+func TestErrorMapping(t *testing.T) {
+
+	type TestCase struct {
+		Name                 string
+		Err                  error
+		ExpectedStatusCode   int
+		ExpectedResponseBody string
+	}
+
+	testCases := []TestCase{
+		{
+			Name:                 "Bad gateway",
+			Err:                  errors.NewBadGateway(),
+			ExpectedStatusCode:   http.StatusBadGateway,
+			ExpectedResponseBody: `{"type":"BAD_GATEWAY","code":"upstream_error","message":"Bad gateway. The upstream server failed to handle the request."}`,
+		},
+		{
+			Name:                 "Too many requests",
+			Err:                  errors.NewTooManyRequests(),
+			ExpectedStatusCode:   http.StatusTooManyRequests,
+			ExpectedResponseBody: `{"type":"TOO_MANY_REQUESTS","code":"rate_limited","message":"Too many requests. Try again later."}`,
+		},
+		{
+			Name:                 "Untyped",
+			Err:                  fmt.Errorf("unexpected end of JSON input"),
+			ExpectedStatusCode:   http.StatusInternalServerError,
+			ExpectedResponseBody: `{"type":"INTERNAL_SERVER_ERROR","code":"internal_error","message":"Internal server error."}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			swapiMock := swapi.MockClient{
+				GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+					return models.Film{}, tc.Err
+				},
+				GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+				GetAllVehiclesFunc: func(ctx context.Context) (models.Vehicles, error) {
+					return models.Vehicles{}, tc.Err
+				},
+				GetAllVehiclesFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			for _, url := range []string{"/api/v1/films/1", "/api/v1/vehicles?all=true"} {
+				response := DoRequest(http.MethodGet, url, nil, "")
+
+				assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode, url)
+				assert.JSONEq(t, tc.ExpectedResponseBody, response.StringBody(), url)
+			}
+		})
+	}
+}
+
+func TestUpstreamErrors(t *testing.T) {
+
+	type TestCase struct {
+		Name                 string
+		UpstreamStatus       int
+		UpstreamRetryAfter   string
+		UpstreamBody         string
+		UpstreamDelay        time.Duration
+		ExpectedStatusCode   int
+		ExpectedRetryAfter   string
+		ExpectedResponseBody string
+	}
+
+	testCases := []TestCase{
+		{
+			Name:                 "Upstream server error",
+			UpstreamStatus:       http.StatusInternalServerError,
+			ExpectedStatusCode:   http.StatusBadGateway,
+			ExpectedResponseBody: `{"type":"BAD_GATEWAY","code":"upstream_error","message":"Bad gateway. The upstream server failed to handle the request.","details":{"upstream_status":500}}`,
+		},
+		{
+			Name:                 "Upstream rate limiting",
+			UpstreamStatus:       http.StatusTooManyRequests,
+			UpstreamRetryAfter:   "120",
+			ExpectedStatusCode:   http.StatusServiceUnavailable,
+			ExpectedRetryAfter:   "120",
+			ExpectedResponseBody: `{"type":"SERVICE_UNAVAILABLE","code":"upstream_rate_limited","message":"Service unavailable. The upstream server is rate limiting requests, try again later."}`,
+		},
+		{
+			Name:                 "Upstream timeout",
+			UpstreamStatus:       http.StatusOK,
+			UpstreamDelay:        200 * time.Millisecond,
+			ExpectedStatusCode:   http.StatusGatewayTimeout,
+			ExpectedResponseBody: `{"type":"GATEWAY_TIMEOUT","code":"upstream_timeout","message":"Gateway timeout. The upstream server didn't respond in time."}`,
+		},
+		{
+			Name:                 "Malformed upstream body",
+			UpstreamStatus:       http.StatusOK,
+			UpstreamBody:         `{"title":`,
+			ExpectedStatusCode:   http.StatusBadGateway,
+			ExpectedResponseBody: `{"type":"UPSTREAM_CONTRACT_VIOLATION","code":"upstream_contract_violation","message":"Bad gateway. The upstream server sent an invalid response."}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			upstream := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				time.Sleep(tc.UpstreamDelay)
+
+				if tc.UpstreamRetryAfter != "" {
+					rw.Header().Set("Retry-After", tc.UpstreamRetryAfter)
+				}
+
+				rw.WriteHeader(tc.UpstreamStatus)
+				fmt.Fprint(rw, tc.UpstreamBody)
+			}))
+			defer upstream.Close()
+
+			options := swapi.DefaultClientOptions()
+			options.BaseURL = upstream.URL
+			options.Retry = swapi.RetryPolicy{MaxAttempts: 1}
+			options.Timeout = 50 * time.Millisecond
+
+			swapi.Instance = swapi.NewSWAPIClient(options)
+			defer swapi.Close(swapi.Instance)
+
+			response := DoRequest(http.MethodGet, "/api/v1/films/1", nil, "")
+
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.ExpectedRetryAfter, response.Headers.Get("Retry-After"))
+			assert.JSONEq(t, tc.ExpectedResponseBody, response.StringBody())
+		})
+	}
+}
+
+func TestContentNegotiation(t *testing.T) {
+
+	type TestCase struct {
+		Name                 string
+		URL                  string
+		Accept               string
+		ExpectedStatusCode   int
+		ExpectedContentType  string
+		ExpectedResponseBody string
+	}
+
+	testCases := []TestCase{
+		{
+			Name:                 "JSON",
+			URL:                  "/api/v1/planets?fields=name,climate",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedContentType:  "application/json",
+			ExpectedResponseBody: `{"count":2,"results":[{"climate":"arid","name":"Tatooine"},{"climate":"frozen","name":"Hoth"}]}`,
+		},
+		{
+			Name:                 "Pretty JSON",
+			URL:                  "/api/v1/planets?fields=name&pretty=true",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedContentType:  "application/json",
+			ExpectedResponseBody: "{\n  \"count\": 2,\n  \"results\": [\n    {\n      \"name\": \"Tatooine\"\n    },\n    {\n      \"name\": \"Hoth\"\n    }\n  ]\n}",
+		},
+		{
+			Name:                 "NDJSON",
+			URL:                  "/api/v1/planets?fields=name",
+			Accept:               "application/x-ndjson",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedContentType:  httphelpers.NDJSONContentType,
+			ExpectedResponseBody: "{\"name\":\"Tatooine\"}\n{\"name\":\"Hoth\"}\n",
+		},
+		{
+			Name:                 "CSV",
+			URL:                  "/api/v1/planets?fields=name,climate",
+			Accept:               "text/csv",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedContentType:  httphelpers.CSVContentType,
+			ExpectedResponseBody: "climate,name\narid,Tatooine\nfrozen,Hoth\n",
+		},
+		{
+			Name:                 "YAML",
+			URL:                  "/api/v1/planets?fields=name",
+			Accept:               "application/yaml",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedContentType:  httphelpers.YAMLContentType,
+			ExpectedResponseBody: "count: 2\nresults:\n  - name: Tatooine\n  - name: Hoth\n",
+		},
+		{
+			Name:                 "Not acceptable",
+			URL:                  "/api/v1/planets",
+			Accept:               "text/html",
+			ExpectedStatusCode:   http.StatusNotAcceptable,
+			ExpectedContentType:  "application/json",
+			ExpectedResponseBody: `{"type":"NOT_ACCEPTABLE","code":"not_acceptable","message":"Not acceptable. Supported media types: application/json, application/x-ndjson, text/csv, application/yaml","details":{"supported":["application/json","application/x-ndjson","text/csv","application/yaml"]}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			swapiMock := swapi.MockClient{
+				GetPlanetsFunc: func(ctx context.Context, page int) (models.Planets, error) {
+					return models.Planets{Count: 2, Results: []models.Planet{{Name: "Tatooine", Climate: "arid"}, {Name: "Hoth", Climate: "frozen"}}}, nil
+				},
+				GetPlanetsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			headers := http.Header{}
+
+			if tc.Accept != "" {
+				headers.Set("Accept", tc.Accept)
+			}
+
+			response := DoRequest(http.MethodGet, tc.URL, headers, "")
+
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.ExpectedContentType, response.Headers.Get("Content-Type"))
+			assert.Equal(t, tc.ExpectedResponseBody, response.StringBody())
+		})
+	}
+}
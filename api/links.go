@@ -0,0 +1,86 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"swapi/errors"
+	"swapi/models"
+	"swapi/utils"
+)
+
+// linkMode is how links to other SWAPI resources are rendered, chosen with
+// the links query parameter
+type linkMode string
+
+const (
+	// linksSWAPI keeps the upstream swapi.dev links untouched
+	linksSWAPI linkMode = "swapi"
+	// linksIDs replaces every link with the bare id of the resource
+	linksIDs linkMode = "ids"
+	// linksAPI points every link at the matching route of this API
+	linksAPI linkMode = "api"
+)
+
+func queryLinks(r *http.Request) (linkMode, error) {
+	switch mode := linkMode(r.URL.Query().Get("links")); mode {
+	case "":
+		return linksSWAPI, nil
+	case linksSWAPI, linksIDs, linksAPI:
+		return mode, nil
+	default:
+		return "", errors.NewBadRequest("invalid links")
+	}
+}
+
+// rewriteLinks returns v with its SWAPI links rendered according to mode
+func rewriteLinks(r *http.Request, mode linkMode, v interface{}) interface{} {
+	if mode == linksSWAPI {
+		return v
+	}
+
+	return rewriteTree(r, mode, toTree(v))
+}
+
+// toTree converts v to its generic JSON representation, made of maps, slices
+// and scalars, so its fields can be edited by name
+func toTree(v interface{}) interface{} {
+	decoder := json.NewDecoder(bytes.NewReader(utils.ToJSON(v)))
+	decoder.UseNumber()
+
+	var tree interface{}
+
+	if err := decoder.Decode(&tree); err != nil {
+		panic(err)
+	}
+
+	return tree
+}
+
+func rewriteTree(r *http.Request, mode linkMode, node interface{}) interface{} {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			value[key] = rewriteTree(r, mode, child)
+		}
+	case []interface{}:
+		for i, child := range value {
+			value[i] = rewriteTree(r, mode, child)
+		}
+	case string:
+		resource, id, ok := models.ParseLink(value)
+
+		if !ok {
+			return value
+		}
+
+		if mode == linksIDs {
+			return id
+		}
+
+		return apiURL(r, fmt.Sprintf("/api/v1/%s/%d", resource, id), "")
+	}
+
+	return node
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"swapi/errors"
+	"swapi/logging"
+	"swapi/metrics"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+var (
+	httpRequests = metrics.Default.NewCounter(
+		"swapi_http_requests_total",
+		"Requests served by route pattern, method and status code.",
+		"route", "method", "code",
+	)
+	httpDuration = metrics.Default.NewHistogram(
+		"swapi_http_request_duration_seconds",
+		"Latency of the requests served by route pattern, method and status code.",
+		metrics.DefaultBuckets,
+		"route", "method", "code",
+	)
+)
+
+// maxRequestIDLength bounds the request IDs accepted from clients
+const maxRequestIDLength = 128
+
+// RequestLogger gives every request an ID, the client's X-Request-ID when it
+// sends a valid one, echoes it back and logs the request once served
+func RequestLogger(logger *logging.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			id := r.Header.Get(logging.RequestIDHeader)
+
+			if !validRequestID(id) {
+				id = logging.NewRequestID()
+			}
+
+			rw.Header().Set(logging.RequestIDHeader, id)
+
+			ctx := logging.WithRequestID(r.Context(), id)
+			ctx, upstreamCalls := logging.WithUpstreamCalls(ctx)
+			ctx, servedErr := logging.WithServedError(ctx)
+
+			ww := middleware.NewWrapResponseWriter(rw, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			status := ww.Status()
+
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			level := logging.LevelInfo
+
+			if status >= http.StatusInternalServerError {
+				level = logging.LevelError
+			} else if status >= http.StatusBadRequest {
+				level = logging.LevelWarn
+			}
+
+			route := routePattern(r)
+
+			fields := logging.Fields{
+				"method":         r.Method,
+				"path":           r.URL.Path,
+				"route":          route,
+				"status":         status,
+				"latency_ms":     float64(time.Since(start).Microseconds()) / 1000,
+				"bytes":          ww.BytesWritten(),
+				"request_id":     id,
+				"upstream_calls": atomic.LoadInt64(upstreamCalls),
+			}
+
+			// causes are only ever logged, clients never see them
+			if err := *servedErr; err != nil {
+				fields["error"] = err.Error()
+
+				if cause := errors.Cause(err); cause != "" {
+					fields["cause"] = cause
+				}
+			}
+
+			logger.Log(level, "request", fields)
+		})
+	}
+}
+
+// RequestMetrics counts and times every request by its route pattern rather
+// than its path, so ids don't make up new series
+func RequestMetrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(rw, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		route := routePattern(r)
+
+		if route == "" {
+			route = "unmatched"
+		}
+
+		code := strconv.Itoa(status)
+
+		httpRequests.Inc(route, r.Method, code)
+		httpDuration.Observe(time.Since(start).Seconds(), route, r.Method, code)
+	})
+}
+
+// routePattern returns the chi route that matched r, once served
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		return rctx.RoutePattern()
+	}
+
+	return ""
+}
+
+// validRequestID accepts short IDs of printable ASCII, so they are safe to log
+// and echo back
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
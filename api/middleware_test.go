@@ -0,0 +1,202 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"swapi/clients/swapi"
+	"swapi/errors"
+	"swapi/logging"
+	"swapi/mockeable"
+	"swapi/models"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestLogger(t *testing.T) {
+
+	type TestCase struct {
+		Name                 string
+		RequestID            string
+		ExpectedEchoedID     bool
+		ExpectedStatusCode   int
+		ExpectedLevel        string
+		ExpectedResponseBody string
+		ExpectedError        string
+		ExpectedCause        string
+		MockError            error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:               "Client request ID",
+			RequestID:          "abc-123",
+			ExpectedEchoedID:   true,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedLevel:      "info",
+		},
+		{
+			Name:               "Missing request ID",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedLevel:      "info",
+		},
+		{
+			Name:               "Invalid request ID",
+			RequestID:          "not valid",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedLevel:      "info",
+		},
+		{
+			Name:                 "Error",
+			RequestID:            "abc-123",
+			ExpectedEchoedID:     true,
+			ExpectedStatusCode:   http.StatusNotFound,
+			ExpectedLevel:        "warn",
+			ExpectedResponseBody: `{"type":"NOT_FOUND","code":"resource_not_found","message":"resource: people with id: 1 not found","request_id":"abc-123"}`,
+			ExpectedError:        "resource: people with id: 1 not found",
+			MockError:            errors.NewNotFound("people", "1"),
+		},
+		{
+			Name:                 "Error with a cause",
+			RequestID:            "abc-123",
+			ExpectedEchoedID:     true,
+			ExpectedStatusCode:   http.StatusInternalServerError,
+			ExpectedLevel:        "error",
+			ExpectedResponseBody: `{"type":"INTERNAL_SERVER_ERROR","code":"internal_error","message":"Internal server error.","request_id":"abc-123"}`,
+			ExpectedError:        "Internal server error.",
+			ExpectedCause:        "decoding body: unexpected EOF",
+			MockError:            errors.NewInternal().WithCause(fmt.Errorf("decoding body: %w", io.ErrUnexpectedEOF)),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			var handledID string
+
+			swapiMock := swapi.MockClient{
+				GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+					handledID = logging.RequestID(ctx)
+
+					return models.People{Name: "Luke Skywalker"}, tc.MockError
+				},
+				GetPeopleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			var logs bytes.Buffer
+
+			router := chi.NewRouter()
+			router.Use(RequestLogger(logging.New(&logs, logging.LevelDebug)))
+			URLMapping(router)
+
+			request := httptest.NewRequest(http.MethodGet, "/api/v1/people/1", nil)
+
+			if tc.RequestID != "" {
+				request.Header.Set(logging.RequestIDHeader, tc.RequestID)
+			}
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			id := recorder.Header().Get(logging.RequestIDHeader)
+
+			if tc.ExpectedEchoedID {
+				assert.Equal(t, tc.RequestID, id)
+			} else {
+				assert.Len(t, id, 32)
+			}
+
+			assert.Equal(t, id, handledID)
+			assert.Equal(t, tc.ExpectedStatusCode, recorder.Code)
+
+			if tc.ExpectedResponseBody != "" {
+				assert.JSONEq(t, tc.ExpectedResponseBody, recorder.Body.String())
+			}
+
+			lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+			assert.Len(t, lines, 1)
+
+			var entry map[string]interface{}
+			assert.Nil(t, json.Unmarshal([]byte(lines[0]), &entry))
+
+			assert.Equal(t, tc.ExpectedLevel, entry["level"])
+			assert.Equal(t, "request", entry["msg"])
+			assert.Equal(t, http.MethodGet, entry["method"])
+			assert.Equal(t, "/api/v1/people/1", entry["path"])
+			assert.Equal(t, "/api/v1/people/{id}", entry["route"])
+			assert.Equal(t, float64(tc.ExpectedStatusCode), entry["status"])
+			assert.Equal(t, float64(recorder.Body.Len()), entry["bytes"])
+			assert.Equal(t, id, entry["request_id"])
+			assert.Equal(t, float64(0), entry["upstream_calls"], "the mock makes no upstream calls")
+			assert.Contains(t, entry, "latency_ms")
+
+			if tc.ExpectedError != "" {
+				assert.Equal(t, tc.ExpectedError, entry["error"])
+			} else {
+				assert.NotContains(t, entry, "error")
+			}
+
+			if tc.ExpectedCause != "" {
+				assert.Equal(t, tc.ExpectedCause, entry["cause"])
+			} else {
+				assert.NotContains(t, entry, "cause")
+			}
+		})
+	}
+}
+
+func TestValidRequestID(t *testing.T) {
+	assert.True(t, validRequestID("f3a9-01"))
+	assert.False(t, validRequestID(""))
+	assert.False(t, validRequestID("with space"))
+	assert.False(t, validRequestID("line\nbreak"))
+	assert.False(t, validRequestID(strings.Repeat("a", maxRequestIDLength+1)))
+}
+
+func TestRequestMetrics(t *testing.T) {
+	swapiMock := swapi.MockClient{
+		GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+			return models.People{Name: "Luke Skywalker"}, nil
+		},
+		GetPeopleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 2},
+	}
+
+	swapiMock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+	router := chi.NewRouter()
+	router.Use(RequestMetrics)
+	URLMapping(router)
+
+	served := httpRequests.Value("/api/v1/people/{id}", http.MethodGet, "200")
+	timed := httpDuration.Count("/api/v1/people/{id}", http.MethodGet, "200")
+	invalid := httpRequests.Value("/api/v1/people/{id}", http.MethodGet, "400")
+	unmatched := httpRequests.Value("unmatched", http.MethodGet, "404")
+
+	for _, path := range []string{"/api/v1/people/1", "/api/v1/people/2", "/api/v1/people/luke", "/unknown"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.Equal(t, served+2, httpRequests.Value("/api/v1/people/{id}", http.MethodGet, "200"), "ids share the route series")
+	assert.Equal(t, timed+2, httpDuration.Count("/api/v1/people/{id}", http.MethodGet, "200"))
+	assert.Equal(t, invalid+1, httpRequests.Value("/api/v1/people/{id}", http.MethodGet, "400"))
+	assert.Equal(t, unmatched+1, httpRequests.Value("unmatched", http.MethodGet, "404"))
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "# TYPE swapi_http_requests_total counter\n")
+	assert.Contains(t, recorder.Body.String(), `swapi_http_requests_total{route="/api/v1/people/{id}",method="GET",code="200"}`)
+	assert.Contains(t, recorder.Body.String(), "# TYPE swapi_upstream_requests_total counter\n")
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"swapi/clients/swapi"
+	"swapi/errors"
+	"swapi/services"
+)
+
+// maxPageSize caps page_size so a single request can't ask for everything
+// through the pagination parameters
+const maxPageSize = 100
+
+// queryBool reads an optional boolean query parameter, defaulting to false
+func queryBool(r *http.Request, name string) (bool, error) {
+	value := r.URL.Query().Get(name)
+
+	if value == "" {
+		return false, nil
+	}
+
+	return strconv.ParseBool(value)
+}
+
+// typedFormat tells whether the response should use the typed representation
+// of the models instead of SWAPI's raw strings, chosen with format=typed
+func typedFormat(r *http.Request) (bool, error) {
+	switch r.URL.Query().Get("format") {
+	case "", "raw":
+		return false, nil
+	case "typed":
+		return true, nil
+	default:
+		return false, errors.NewBadRequest("invalid format")
+	}
+}
+
+// queryList reads an optional comma separated query parameter whose items
+// must be among valid
+func queryList(r *http.Request, name string, valid []string) ([]string, error) {
+	value := r.URL.Query().Get(name)
+
+	if value == "" {
+		return nil, nil
+	}
+
+	var items []string
+	seen := map[string]bool{}
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+
+		if !contains(valid, item) {
+			return nil, errors.NewBadRequest(fmt.Sprintf("invalid %s %q, valid values are: %s", name, item, strings.Join(valid, ", ")))
+		}
+
+		if !seen[item] {
+			seen[item] = true
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reservedParams are the list parameters that aren't filters
+var reservedParams = []string{"page", "page_size", "all", "search", "sort", "fields", "links", "format", "expand", "pretty"}
+
+// listQuery reads the filters and sort keys of a list request. Every
+// parameter but the reserved ones is a filter named after a field, optionally
+// suffixed with an operator such as _lt, unknown fields are rejected by the
+// service. Sort keys are comma separated, prefixed with - for a descending
+// order.
+func listQuery(r *http.Request) services.Query {
+	var query services.Query
+
+	params := r.URL.Query()
+	names := make([]string, 0, len(params))
+
+	for name := range params {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if contains(reservedParams, name) {
+			continue
+		}
+
+		field, operator := services.ParseFilter(name)
+
+		for _, value := range params[name] {
+			query.Filters = append(query.Filters, services.Filter{Field: field, Operator: operator, Value: value})
+		}
+	}
+
+	if value := params.Get("sort"); value != "" {
+		for _, key := range strings.Split(value, ",") {
+			key = strings.TrimSpace(key)
+
+			query.Sort = append(query.Sort, services.SortKey{
+				Field:      strings.TrimPrefix(key, "-"),
+				Descending: strings.HasPrefix(key, "-"),
+			})
+		}
+	}
+
+	return query
+}
+
+// queryInt reads an optional positive integer query parameter
+func queryInt(r *http.Request, name string, defaultValue int) (int, error) {
+	value := r.URL.Query().Get(name)
+
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	n, err := strconv.Atoi(value)
+
+	if err != nil || n < 1 {
+		return 0, errors.NewBadRequest(fmt.Sprintf("invalid %s", name))
+	}
+
+	return n, nil
+}
+
+// pagination reads page and page_size, defaulting to SWAPI's first page
+func pagination(r *http.Request) (page int, pageSize int, err error) {
+	page, err = queryInt(r, "page", 1)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	pageSize, err = queryInt(r, "page_size", swapi.PageSize)
+
+	if err != nil || pageSize > maxPageSize {
+		return 0, 0, errors.NewBadRequest("invalid page_size")
+	}
+
+	return page, pageSize, nil
+}
+
+// pageLinks builds the next and previous links of a list response pointing
+// back at this API, keeping every other query parameter untouched
+func pageLinks(r *http.Request, page int, pageSize int, count int) (next string, previous string) {
+	link := func(page int) string {
+		query := r.URL.Query()
+		query.Set("page", strconv.Itoa(page))
+
+		return apiURL(r, r.URL.Path, query.Encode())
+	}
+
+	if page*pageSize < count {
+		next = link(page + 1)
+	}
+
+	if page > 1 {
+		previous = link(page - 1)
+	}
+
+	return next, previous
+}
+
+// apiURL builds an absolute URL to path on the host the request was sent to
+func apiURL(r *http.Request, path string, rawQuery string) string {
+	scheme := "http"
+
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	u := url.URL{
+		Scheme:   scheme,
+		Host:     r.Host,
+		Path:     path,
+		RawQuery: rawQuery,
+	}
+
+	return u.String()
+}
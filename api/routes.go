@@ -1,12 +1,28 @@
 package api
 
-import "github.com/go-chi/chi/v5"
+import (
+	"swapi/metrics"
+
+	"github.com/go-chi/chi/v5"
+)
 
 func URLMapping(router *chi.Mux) {
+	router.Get("/metrics", metrics.Handler(metrics.Default))
+	router.Get("/healthz", GetHealthHandler)
+	router.Get("/readyz", GetReadinessHandler)
 	router.Route("/api/v1", func(r chi.Router) {
-		r.Get("/starships/{id}", GetStarshipHandler)
-		r.Get("/starships", GetStarshipsHandler)
-		r.Get("/people/{id}", GetPeopleHandler)
-		r.Get("/people", GetPeopleListHandler)
+		r.Get("/starships/{id}", traced("starships", GetStarshipHandler))
+		r.Get("/starships", traced("starships", GetStarshipsHandler))
+		r.Get("/people/{id}", traced("people", GetPeopleHandler))
+		r.Get("/people", traced("people", GetPeopleListHandler))
+		r.Get("/films/{id}", traced("films", GetFilmHandler))
+		r.Get("/films", traced("films", GetFilmsHandler))
+		r.Get("/planets/{id}", traced("planets", GetPlanetHandler))
+		r.Get("/planets", traced("planets", GetPlanetsHandler))
+		r.Get("/species/{id}", traced("species", GetSpeciesHandler))
+		r.Get("/species", traced("species", GetSpeciesListHandler))
+		r.Get("/vehicles/{id}", traced("vehicles", GetVehicleHandler))
+		r.Get("/vehicles", traced("vehicles", GetVehiclesHandler))
+		r.Get("/diagnostics", GetDiagnosticsHandler)
 	})
 }
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"swapi/tracing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// traced serves a resource route in a server span continuing the caller's
+// trace, if it sent a traceparent, with the resource, id and status
+func traced(resource string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		ctx, span := tracing.Start(tracing.Extract(r.Context(), r.Header), tracing.KindServer, r.Method+" "+routePattern(r), tracing.Attributes{
+			"http.method":    r.Method,
+			"http.target":    r.URL.RequestURI(),
+			"http.route":     routePattern(r),
+			"swapi.resource": resource,
+		})
+
+		if id, err := strconv.Atoi(chi.URLParam(r, "id")); err == nil {
+			span.SetAttributes(tracing.Attributes{"swapi.id": id})
+		}
+
+		ww := middleware.NewWrapResponseWriter(rw, r.ProtoMajor)
+
+		handler(ww, r.WithContext(ctx))
+
+		status := ww.Status()
+
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		span.SetAttributes(tracing.Attributes{"http.status_code": status})
+
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(tracing.StatusError, http.StatusText(status))
+		}
+
+		span.End(nil)
+	}
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"swapi/clients/swapi"
+	"swapi/tracing"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracing(t *testing.T) {
+	var upstreamTraceparent string
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		upstreamTraceparent = r.Header.Get(tracing.TraceparentHeader)
+
+		if r.URL.Path == "/films/" {
+			fmt.Fprint(rw, `{"count":1,"results":[{"title":"A New Hope"}]}`)
+			return
+		}
+
+		if r.URL.Path != "/people/1/" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		fmt.Fprint(rw, `{"name":"Luke Skywalker"}`)
+	}))
+	defer upstream.Close()
+
+	options := swapi.DefaultClientOptions()
+	options.BaseURL = upstream.URL
+
+	swapi.Instance = swapi.NewSWAPIClient(options)
+	defer swapi.Close(swapi.Instance)
+
+	exporter := tracing.NewMemoryExporter()
+	tracing.Instance = tracing.New(exporter)
+	defer func() { tracing.Instance = nil }()
+
+	t.Run("Request path", func(t *testing.T) {
+		exporter.Reset()
+
+		headers := http.Header{}
+		headers.Set(tracing.TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		response := DoRequest(http.MethodGet, "/api/v1/people/1", headers, "")
+
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+
+		spans := exporter.Spans()
+		assert.Len(t, spans, 3)
+
+		client, service, server := spans[0], spans[1], spans[2]
+
+		assert.Equal(t, "GET /api/v1/people/{id}", server.Name)
+		assert.Equal(t, tracing.KindServer, server.Kind)
+		assert.Equal(t, "00f067aa0ba902b7", server.Parent.String(), "continues the caller's trace")
+		assert.Equal(t, "people", server.Attributes["swapi.resource"])
+		assert.Equal(t, 1, server.Attributes["swapi.id"])
+		assert.Equal(t, http.StatusOK, server.Attributes["http.status_code"])
+		assert.Equal(t, tracing.StatusUnset, server.Status)
+
+		assert.Equal(t, "services.GetPeopleService", service.Name)
+		assert.Equal(t, server.Context.SpanID, service.Parent)
+		assert.Equal(t, "people", service.Attributes["swapi.resource"])
+		assert.Equal(t, 1, service.Attributes["swapi.id"])
+
+		assert.Equal(t, "swapi GET /people", client.Name)
+		assert.Equal(t, tracing.KindClient, client.Kind)
+		assert.Equal(t, service.Context.SpanID, client.Parent)
+		assert.Equal(t, 1, client.Attributes["swapi.id"])
+		assert.Equal(t, http.StatusOK, client.Attributes["http.status_code"])
+
+		for _, span := range spans {
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.Context.TraceID.String())
+		}
+
+		assert.Equal(t, client.Context.Traceparent(), upstreamTraceparent, "propagated upstream")
+	})
+
+	t.Run("Error status", func(t *testing.T) {
+		exporter.Reset()
+
+		response := DoRequest(http.MethodGet, "/api/v1/people/2", nil, "")
+
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+		spans := exporter.Spans()
+		assert.Len(t, spans, 3)
+
+		client, service, server := spans[0], spans[1], spans[2]
+
+		assert.False(t, server.Parent.IsValid(), "new trace")
+		assert.Equal(t, http.StatusNotFound, server.Attributes["http.status_code"])
+		assert.Equal(t, tracing.StatusUnset, server.Status, "4xx aren't server errors")
+
+		assert.Equal(t, tracing.StatusError, service.Status)
+		assert.Equal(t, "resource: people with id: 2 not found", service.StatusMessage)
+
+		assert.Equal(t, tracing.StatusError, client.Status)
+		assert.Equal(t, http.StatusNotFound, client.Attributes["http.status_code"])
+	})
+
+	t.Run("Whole collection", func(t *testing.T) {
+		exporter.Reset()
+
+		response := DoRequest(http.MethodGet, "/api/v1/films?all=true", nil, "")
+
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+
+		spans := exporter.Spans()
+		assert.Len(t, spans, 3)
+
+		client, service, server := spans[0], spans[1], spans[2]
+
+		assert.Equal(t, "services.GetAllFilmsService", service.Name)
+		assert.Equal(t, server.Context.SpanID, service.Parent)
+		assert.Equal(t, "films", service.Attributes["swapi.resource"])
+		assert.Equal(t, service.Context.SpanID, client.Parent)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		exporter.Reset()
+		tracing.Instance = nil
+
+		response := DoRequest(http.MethodGet, "/api/v1/people/1", nil, "")
+
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Empty(t, exporter.Spans())
+		assert.Empty(t, upstreamTraceparent)
+	})
+}
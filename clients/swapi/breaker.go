@@ -0,0 +1,169 @@
+package swapi
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+type BreakerOptions struct {
+	// FailureThreshold is how many consecutive upstream failures open the
+	// breaker. Zero disables it
+	FailureThreshold int
+	// CoolDown is how long the breaker stays open before letting a probe
+	// call through
+	CoolDown time.Duration
+}
+
+func DefaultBreakerOptions() BreakerOptions {
+	return BreakerOptions{
+		FailureThreshold: 5,
+		CoolDown:         30 * time.Second,
+	}
+}
+
+type BreakerStats struct {
+	State               BreakerState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	OpenedAt            *time.Time   `json:"opened_at,omitempty"`
+}
+
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	// outcomeIgnored is used when the call was abandoned by our own caller,
+	// which says nothing about the upstream health
+	outcomeIgnored
+)
+
+// CircuitBreaker stops calling the upstream after repeated failures, so
+// requests fail fast while it is down. A nil *CircuitBreaker always allows
+// calls.
+type CircuitBreaker struct {
+	options BreakerOptions
+	now     func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func NewCircuitBreaker(options BreakerOptions) *CircuitBreaker {
+	return &CircuitBreaker{
+		options: options,
+		now:     time.Now,
+		state:   BreakerClosed,
+	}
+}
+
+func (b *CircuitBreaker) Stats() BreakerStats {
+	if b == nil {
+		return BreakerStats{State: BreakerClosed}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := BreakerStats{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+	}
+
+	if b.state != BreakerClosed {
+		openedAt := b.openedAt
+		stats.OpenedAt = &openedAt
+	}
+
+	return stats
+}
+
+// allow tells whether a call may go upstream. Once the cool down is over, a
+// single probe call is let through while the breaker is half-open.
+func (b *CircuitBreaker) allow() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.options.CoolDown {
+			return false
+		}
+
+		b.state = BreakerHalfOpen
+		b.probing = true
+
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+
+		b.probing = true
+
+		return true
+	default:
+		return true
+	}
+}
+
+// record reports how an allowed call went
+func (b *CircuitBreaker) record(result outcome) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerHalfOpen {
+		b.probing = false
+	}
+
+	switch result {
+	case outcomeSuccess:
+		b.state = BreakerClosed
+		b.failures = 0
+	case outcomeFailure:
+		b.failures++
+
+		if b.state == BreakerHalfOpen || b.failures >= b.options.FailureThreshold {
+			b.state = BreakerOpen
+			b.openedAt = b.now()
+		}
+	}
+}
+
+// callOutcome classifies an upstream call for the breaker. Only errors that
+// point at the upstream count as failures; a 404 is a healthy answer.
+func callOutcome(ctx context.Context, res *http.Response, err error) outcome {
+	if ctx.Err() != nil {
+		return outcomeIgnored
+	}
+
+	if err != nil {
+		return outcomeFailure
+	}
+
+	if res.StatusCode >= http.StatusInternalServerError || res.StatusCode == http.StatusTooManyRequests {
+		return outcomeFailure
+	}
+
+	return outcomeSuccess
+}
@@ -0,0 +1,165 @@
+package swapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"swapi/errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	newBreaker := func() (*CircuitBreaker, *clock) {
+		clk := &clock{current: time.Date(2022, 5, 4, 0, 0, 0, 0, time.UTC)}
+
+		breaker := NewCircuitBreaker(BreakerOptions{FailureThreshold: 3, CoolDown: time.Minute})
+		breaker.now = clk.now
+
+		return breaker, clk
+	}
+
+	t.Run("Opens after threshold", func(t *testing.T) {
+		breaker, _ := newBreaker()
+
+		for i := 0; i < 2; i++ {
+			assert.True(t, breaker.allow())
+			breaker.record(outcomeFailure)
+		}
+
+		assert.Equal(t, BreakerClosed, breaker.Stats().State)
+
+		assert.True(t, breaker.allow())
+		breaker.record(outcomeFailure)
+
+		assert.Equal(t, BreakerOpen, breaker.Stats().State)
+		assert.Equal(t, 3, breaker.Stats().ConsecutiveFailures)
+		assert.False(t, breaker.allow())
+	})
+
+	t.Run("Success resets failures", func(t *testing.T) {
+		breaker, _ := newBreaker()
+
+		breaker.record(outcomeFailure)
+		breaker.record(outcomeFailure)
+		breaker.record(outcomeSuccess)
+		breaker.record(outcomeFailure)
+		breaker.record(outcomeFailure)
+
+		assert.Equal(t, BreakerClosed, breaker.Stats().State)
+		assert.Equal(t, 2, breaker.Stats().ConsecutiveFailures)
+	})
+
+	t.Run("Half-open probe closes", func(t *testing.T) {
+		breaker, clk := newBreaker()
+
+		for i := 0; i < 3; i++ {
+			breaker.record(outcomeFailure)
+		}
+
+		clk.advance(time.Minute)
+
+		assert.True(t, breaker.allow())
+		assert.Equal(t, BreakerHalfOpen, breaker.Stats().State)
+
+		// only one probe at a time
+		assert.False(t, breaker.allow())
+
+		breaker.record(outcomeSuccess)
+
+		assert.Equal(t, BreakerStats{State: BreakerClosed}, breaker.Stats())
+		assert.True(t, breaker.allow())
+	})
+
+	t.Run("Half-open probe failure reopens", func(t *testing.T) {
+		breaker, clk := newBreaker()
+
+		for i := 0; i < 3; i++ {
+			breaker.record(outcomeFailure)
+		}
+
+		clk.advance(time.Minute)
+
+		assert.True(t, breaker.allow())
+		breaker.record(outcomeFailure)
+
+		assert.Equal(t, BreakerOpen, breaker.Stats().State)
+		assert.Equal(t, clk.current, *breaker.Stats().OpenedAt)
+		assert.False(t, breaker.allow())
+	})
+
+	t.Run("Ignored probe lets another one through", func(t *testing.T) {
+		breaker, clk := newBreaker()
+
+		for i := 0; i < 3; i++ {
+			breaker.record(outcomeFailure)
+		}
+
+		clk.advance(time.Minute)
+
+		assert.True(t, breaker.allow())
+		breaker.record(outcomeIgnored)
+
+		assert.Equal(t, BreakerHalfOpen, breaker.Stats().State)
+		assert.True(t, breaker.allow())
+	})
+
+	t.Run("Nil breaker", func(t *testing.T) {
+		var breaker *CircuitBreaker
+
+		assert.True(t, breaker.allow())
+		breaker.record(outcomeFailure)
+		assert.Equal(t, BreakerClosed, breaker.Stats().State)
+	})
+}
+
+func TestClientCircuitBreaker(t *testing.T) {
+	var calls int32
+	var healthy int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		if atomic.LoadInt32(&healthy) == 0 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		rw.Write([]byte(`{"name":"Death Star"}`))
+	}))
+	defer server.Close()
+
+	clk := &clock{current: time.Date(2022, 5, 4, 0, 0, 0, 0, time.UTC)}
+
+	options := DefaultClientOptions()
+	options.BaseURL = server.URL
+	options.Retry = RetryPolicy{MaxAttempts: 1}
+	options.Breaker = BreakerOptions{FailureThreshold: 2, CoolDown: time.Minute}
+
+	client := NewSWAPIClient(options)
+	client.breaker.now = clk.now
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetStarship(context.Background(), 9)
+		assert.ErrorIs(t, err, errors.NewBadGateway())
+	}
+
+	_, err := client.GetStarship(context.Background(), 9)
+
+	assert.Equal(t, errors.NewUpstreamUnavailable(), err)
+	assert.Equal(t, http.StatusServiceUnavailable, errors.Status(err))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, BreakerOpen, client.Diagnostics().Breaker.State)
+
+	atomic.StoreInt32(&healthy, 1)
+	clk.advance(time.Minute)
+
+	result, err := client.GetStarship(context.Background(), 9)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Death Star", result.Name)
+	assert.Equal(t, BreakerClosed, client.Diagnostics().Breaker.State)
+}
@@ -0,0 +1,378 @@
+package swapi
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"net/http"
+	"swapi/errors"
+	"swapi/models"
+	"sync"
+	"time"
+)
+
+type CacheOptions struct {
+	// DefaultTTL applies to every resource without an entry in TTLs
+	DefaultTTL time.Duration
+	// TTLs overrides DefaultTTL per resource, e.g. "starships"
+	TTLs map[string]time.Duration
+	// NotFoundTTL is how long a 404 is remembered. Zero disables negative caching
+	NotFoundTTL time.Duration
+	// MaxEntries bounds the cache size, evicting the least recently used entry
+	MaxEntries int
+	// SweepInterval is how often expired entries are purged in the background.
+	// Zero disables the sweeper and entries are only dropped when looked up
+	SweepInterval time.Duration
+}
+
+func DefaultCacheOptions() CacheOptions {
+	return CacheOptions{
+		DefaultTTL:    10 * time.Minute,
+		NotFoundTTL:   time.Minute,
+		MaxEntries:    1000,
+		SweepInterval: time.Minute,
+	}
+}
+
+type CacheStats struct {
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Entries int    `json:"entries"`
+}
+
+type cacheEntry struct {
+	key       string
+	value     interface{}
+	err       error
+	expiresAt time.Time
+}
+
+// CachedClient is a Client decorator that keeps upstream responses in memory
+type CachedClient struct {
+	next    Client
+	options CacheOptions
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	hits    uint64
+	misses  uint64
+
+	stop      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// NewCachedClient wraps next in a cache. When options.SweepInterval is set it
+// starts a sweeper goroutine, stopped by Close.
+func NewCachedClient(next Client, options CacheOptions) *CachedClient {
+	c := &CachedClient{
+		next:    next,
+		options: options,
+		now:     time.Now,
+		entries: map[string]*list.Element{},
+		lru:     list.New(),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	if options.SweepInterval > 0 {
+		go c.sweepEvery(options.SweepInterval)
+	} else {
+		close(c.stopped)
+	}
+
+	return c
+}
+
+// Close stops the sweeper, waiting for it to return, and closes the wrapped
+// client. It is safe to call more than once.
+func (c *CachedClient) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+	})
+
+	<-c.stopped
+
+	return Close(c.next)
+}
+
+// Ping always reaches the wrapped client, cached responses don't tell whether
+// the upstream is reachable
+func (c *CachedClient) Ping(ctx context.Context) error {
+	return Ping(ctx, c.next)
+}
+
+func (c *CachedClient) sweepEvery(interval time.Duration) {
+	defer close(c.stopped)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.sweep()
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// sweep removes every expired entry
+func (c *CachedClient) sweep() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+
+	for element := c.lru.Back(); element != nil; {
+		previous := element.Prev()
+		entry := element.Value.(*cacheEntry)
+
+		if !now.Before(entry.expiresAt) {
+			c.lru.Remove(element)
+			delete(c.entries, entry.key)
+		}
+
+		element = previous
+	}
+}
+
+func (c *CachedClient) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return CacheStats{
+		Hits:    c.hits,
+		Misses:  c.misses,
+		Entries: c.lru.Len(),
+	}
+}
+
+func (c *CachedClient) Diagnostics() Diagnostics {
+	diagnostics := Diagnose(c.next)
+	stats := c.Stats()
+	diagnostics.Cache = &stats
+
+	return diagnostics
+}
+
+func (c *CachedClient) GetStarship(ctx context.Context, id int) (models.Starship, error) {
+	return cached(c, "starships", fmt.Sprint(id), func() (models.Starship, error) {
+		return c.next.GetStarship(ctx, id)
+	})
+}
+
+func (c *CachedClient) GetStarships(ctx context.Context, page int) (models.Starships, error) {
+	return cached(c, "starships", fmt.Sprintf("?page=%d", page), func() (models.Starships, error) {
+		return c.next.GetStarships(ctx, page)
+	})
+}
+
+func (c *CachedClient) GetAllStarships(ctx context.Context) (models.Starships, error) {
+	return cached(c, "starships", "?all", func() (models.Starships, error) {
+		return c.next.GetAllStarships(ctx)
+	})
+}
+
+func (c *CachedClient) SearchStarships(ctx context.Context, query string) (models.Starships, error) {
+	return cached(c, "starships", "?search="+query, func() (models.Starships, error) {
+		return c.next.SearchStarships(ctx, query)
+	})
+}
+
+func (c *CachedClient) GetPeople(ctx context.Context, id int) (models.People, error) {
+	return cached(c, "people", fmt.Sprint(id), func() (models.People, error) {
+		return c.next.GetPeople(ctx, id)
+	})
+}
+
+func (c *CachedClient) GetPeopleList(ctx context.Context, page int) (models.PeopleList, error) {
+	return cached(c, "people", fmt.Sprintf("?page=%d", page), func() (models.PeopleList, error) {
+		return c.next.GetPeopleList(ctx, page)
+	})
+}
+
+func (c *CachedClient) GetAllPeople(ctx context.Context) (models.PeopleList, error) {
+	return cached(c, "people", "?all", func() (models.PeopleList, error) {
+		return c.next.GetAllPeople(ctx)
+	})
+}
+
+func (c *CachedClient) SearchPeople(ctx context.Context, query string) (models.PeopleList, error) {
+	return cached(c, "people", "?search="+query, func() (models.PeopleList, error) {
+		return c.next.SearchPeople(ctx, query)
+	})
+}
+
+func (c *CachedClient) GetFilm(ctx context.Context, id int) (models.Film, error) {
+	return cached(c, "films", fmt.Sprint(id), func() (models.Film, error) {
+		return c.next.GetFilm(ctx, id)
+	})
+}
+
+func (c *CachedClient) GetFilms(ctx context.Context, page int) (models.Films, error) {
+	return cached(c, "films", fmt.Sprintf("?page=%d", page), func() (models.Films, error) {
+		return c.next.GetFilms(ctx, page)
+	})
+}
+
+func (c *CachedClient) GetAllFilms(ctx context.Context) (models.Films, error) {
+	return cached(c, "films", "?all", func() (models.Films, error) {
+		return c.next.GetAllFilms(ctx)
+	})
+}
+
+func (c *CachedClient) GetPlanet(ctx context.Context, id int) (models.Planet, error) {
+	return cached(c, "planets", fmt.Sprint(id), func() (models.Planet, error) {
+		return c.next.GetPlanet(ctx, id)
+	})
+}
+
+func (c *CachedClient) GetPlanets(ctx context.Context, page int) (models.Planets, error) {
+	return cached(c, "planets", fmt.Sprintf("?page=%d", page), func() (models.Planets, error) {
+		return c.next.GetPlanets(ctx, page)
+	})
+}
+
+func (c *CachedClient) GetAllPlanets(ctx context.Context) (models.Planets, error) {
+	return cached(c, "planets", "?all", func() (models.Planets, error) {
+		return c.next.GetAllPlanets(ctx)
+	})
+}
+
+func (c *CachedClient) GetSpecies(ctx context.Context, id int) (models.Species, error) {
+	return cached(c, "species", fmt.Sprint(id), func() (models.Species, error) {
+		return c.next.GetSpecies(ctx, id)
+	})
+}
+
+func (c *CachedClient) GetSpeciesList(ctx context.Context, page int) (models.SpeciesList, error) {
+	return cached(c, "species", fmt.Sprintf("?page=%d", page), func() (models.SpeciesList, error) {
+		return c.next.GetSpeciesList(ctx, page)
+	})
+}
+
+func (c *CachedClient) GetAllSpecies(ctx context.Context) (models.SpeciesList, error) {
+	return cached(c, "species", "?all", func() (models.SpeciesList, error) {
+		return c.next.GetAllSpecies(ctx)
+	})
+}
+
+func (c *CachedClient) GetVehicle(ctx context.Context, id int) (models.Vehicle, error) {
+	return cached(c, "vehicles", fmt.Sprint(id), func() (models.Vehicle, error) {
+		return c.next.GetVehicle(ctx, id)
+	})
+}
+
+func (c *CachedClient) GetVehicles(ctx context.Context, page int) (models.Vehicles, error) {
+	return cached(c, "vehicles", fmt.Sprintf("?page=%d", page), func() (models.Vehicles, error) {
+		return c.next.GetVehicles(ctx, page)
+	})
+}
+
+func (c *CachedClient) GetAllVehicles(ctx context.Context) (models.Vehicles, error) {
+	return cached(c, "vehicles", "?all", func() (models.Vehicles, error) {
+		return c.next.GetAllVehicles(ctx)
+	})
+}
+
+// cached returns the entry stored under resource/key or calls fetch and
+// stores its result. Only successful responses and 404s are cached.
+func cached[T any](c *CachedClient, resource string, key string, fetch func() (T, error)) (T, error) {
+	key = resource + "/" + key
+
+	entry, ok := c.lookup(key)
+
+	if ok {
+		cacheRequests.Inc(resource, "hit")
+
+		if entry.err != nil {
+			var zero T
+			return zero, entry.err
+		}
+
+		return entry.value.(T), nil
+	}
+
+	cacheRequests.Inc(resource, "miss")
+
+	result, err := fetch()
+
+	switch {
+	case err == nil:
+		c.store(key, result, nil, c.ttl(resource))
+	case errors.Status(err) == http.StatusNotFound && c.options.NotFoundTTL > 0:
+		c.store(key, result, err, c.options.NotFoundTTL)
+	}
+
+	return result, err
+}
+
+func (c *CachedClient) ttl(resource string) time.Duration {
+	if ttl, ok := c.options.TTLs[resource]; ok {
+		return ttl
+	}
+
+	return c.options.DefaultTTL
+}
+
+func (c *CachedClient) lookup(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+
+	if !c.now().Before(entry.expiresAt) {
+		c.lru.Remove(element)
+		delete(c.entries, key)
+		c.misses++
+
+		return nil, false
+	}
+
+	c.lru.MoveToFront(element)
+	c.hits++
+
+	return entry, true
+}
+
+func (c *CachedClient) store(key string, value interface{}, err error, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry{
+		key:       key,
+		value:     value,
+		err:       err,
+		expiresAt: c.now().Add(ttl),
+	}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.lru.MoveToFront(element)
+
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(entry)
+
+	for c.options.MaxEntries > 0 && c.lru.Len() > c.options.MaxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
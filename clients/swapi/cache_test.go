@@ -0,0 +1,284 @@
+package swapi
+
+import (
+	"context"
+	"swapi/errors"
+	"swapi/mockeable"
+	"swapi/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// clock is a controllable time source for the cache
+type clock struct {
+	current time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.current
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.current = c.current.Add(d)
+}
+
+func newTestCachedClient(next Client, options CacheOptions) (*CachedClient, *clock) {
+	clk := &clock{current: time.Date(2022, 5, 4, 0, 0, 0, 0, time.UTC)}
+
+	client := NewCachedClient(next, options)
+	client.now = clk.now
+
+	return client, clk
+}
+
+func TestCachedClient(t *testing.T) {
+	options := CacheOptions{
+		DefaultTTL:  time.Hour,
+		TTLs:        map[string]time.Duration{"people": time.Minute},
+		NotFoundTTL: 10 * time.Second,
+		MaxEntries:  2,
+	}
+
+	t.Run("Hit", func(t *testing.T) {
+		swapiMock := MockClient{
+			GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
+				return models.Starship{Name: "Death Star"}, nil
+			},
+			GetStarshipFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+		}
+
+		swapiMock.Use()
+		defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+		client, _ := newTestCachedClient(&swapiMock, options)
+
+		hits, misses := cacheRequests.Value("starships", "hit"), cacheRequests.Value("starships", "miss")
+
+		for i := 0; i < 3; i++ {
+			result, err := client.GetStarship(context.Background(), 9)
+
+			assert.Nil(t, err)
+			assert.Equal(t, "Death Star", result.Name)
+		}
+
+		assert.Equal(t, CacheStats{Hits: 2, Misses: 1, Entries: 1}, client.Stats())
+		assert.Equal(t, hits+2, cacheRequests.Value("starships", "hit"))
+		assert.Equal(t, misses+1, cacheRequests.Value("starships", "miss"))
+	})
+
+	t.Run("Per resource TTL", func(t *testing.T) {
+		swapiMock := MockClient{
+			GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
+				return models.Starship{Name: "Death Star"}, nil
+			},
+			GetStarshipFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+			GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+				return models.People{Name: "Luke Skywalker"}, nil
+			},
+			GetPeopleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 2},
+		}
+
+		swapiMock.Use()
+		defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+		client, clk := newTestCachedClient(&swapiMock, options)
+
+		client.GetStarship(context.Background(), 9)
+		client.GetPeople(context.Background(), 1)
+
+		clk.advance(2 * time.Minute)
+
+		client.GetStarship(context.Background(), 9)
+		client.GetPeople(context.Background(), 1)
+	})
+
+	t.Run("Negative caching", func(t *testing.T) {
+		swapiMock := MockClient{
+			GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+				return models.Film{}, errors.NewNotFound("films", "99")
+			},
+			GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 2},
+		}
+
+		swapiMock.Use()
+		defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+		client, clk := newTestCachedClient(&swapiMock, options)
+
+		_, err := client.GetFilm(context.Background(), 99)
+		assert.Equal(t, errors.NewNotFound("films", "99"), err)
+
+		_, err = client.GetFilm(context.Background(), 99)
+		assert.Equal(t, errors.NewNotFound("films", "99"), err)
+
+		clk.advance(11 * time.Second)
+
+		_, err = client.GetFilm(context.Background(), 99)
+		assert.Equal(t, errors.NewNotFound("films", "99"), err)
+	})
+
+	t.Run("Errors are not cached", func(t *testing.T) {
+		swapiMock := MockClient{
+			GetPlanetsFunc: func(ctx context.Context, page int) (models.Planets, error) {
+				return models.Planets{}, errors.NewInternal()
+			},
+			GetPlanetsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 2},
+		}
+
+		swapiMock.Use()
+		defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+		client, _ := newTestCachedClient(&swapiMock, options)
+
+		client.GetPlanets(context.Background(), 1)
+		client.GetPlanets(context.Background(), 1)
+
+		assert.Equal(t, 0, client.Stats().Entries)
+	})
+
+	t.Run("LRU eviction", func(t *testing.T) {
+		calls := map[int]int{}
+
+		swapiMock := MockClient{
+			GetVehicleFunc: func(ctx context.Context, id int) (models.Vehicle, error) {
+				calls[id]++
+				return models.Vehicle{}, nil
+			},
+			GetVehicleFuncControl: mockeable.CallsFuncControl{IgnoreCallsAssertion: true},
+		}
+
+		swapiMock.Use()
+		defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+		client, _ := newTestCachedClient(&swapiMock, options)
+
+		client.GetVehicle(context.Background(), 1)
+		client.GetVehicle(context.Background(), 2)
+		client.GetVehicle(context.Background(), 1)
+		client.GetVehicle(context.Background(), 3)
+		client.GetVehicle(context.Background(), 1)
+		client.GetVehicle(context.Background(), 2)
+
+		assert.Equal(t, map[int]int{1: 1, 2: 2, 3: 1}, calls)
+		assert.Equal(t, 2, client.Stats().Entries)
+	})
+
+	t.Run("Keys", func(t *testing.T) {
+		swapiMock := MockClient{
+			GetPeopleListFunc: func(ctx context.Context, page int) (models.PeopleList, error) {
+				return models.PeopleList{}, nil
+			},
+			GetPeopleListFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 2},
+			SearchPeopleFunc: func(ctx context.Context, query string) (models.PeopleList, error) {
+				return models.PeopleList{}, nil
+			},
+			SearchPeopleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+		}
+
+		swapiMock.Use()
+		defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+		client, _ := newTestCachedClient(&swapiMock, CacheOptions{DefaultTTL: time.Hour})
+
+		client.GetPeopleList(context.Background(), 1)
+		client.GetPeopleList(context.Background(), 2)
+		client.GetPeopleList(context.Background(), 1)
+		client.SearchPeople(context.Background(), "luke")
+		client.SearchPeople(context.Background(), "luke")
+	})
+}
+
+func TestCachedClientDiagnostics(t *testing.T) {
+	t.Run("Without upstream diagnostics", func(t *testing.T) {
+		client, _ := newTestCachedClient(&MockClient{}, CacheOptions{})
+
+		assert.Equal(t, Diagnostics{Cache: &CacheStats{}}, client.Diagnostics())
+	})
+
+	t.Run("Forwards upstream diagnostics", func(t *testing.T) {
+		options := DefaultClientOptions()
+		client, _ := newTestCachedClient(NewCoalescingClient(NewSWAPIClient(options)), CacheOptions{})
+
+		diagnostics := client.Diagnostics()
+
+		assert.Equal(t, &BreakerStats{State: BreakerClosed}, diagnostics.Breaker)
+		assert.Equal(t, &CacheStats{}, diagnostics.Cache)
+	})
+}
+
+// closeCounter is a client that counts how many times it was closed
+type closeCounter struct {
+	MockClient
+	closed int
+}
+
+func (c *closeCounter) Close() error {
+	c.closed++
+
+	return nil
+}
+
+func TestCachedClientSweep(t *testing.T) {
+	swapiMock := MockClient{
+		GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
+			return models.Starship{}, nil
+		},
+		GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+			return models.People{}, nil
+		},
+	}
+
+	client, clk := newTestCachedClient(&swapiMock, CacheOptions{
+		DefaultTTL: time.Hour,
+		TTLs:       map[string]time.Duration{"people": time.Minute},
+	})
+
+	client.GetStarship(context.Background(), 1)
+	client.GetPeople(context.Background(), 1)
+	client.GetPeople(context.Background(), 2)
+
+	clk.advance(2 * time.Minute)
+	client.sweep()
+
+	assert.Equal(t, 1, client.Stats().Entries)
+
+	clk.advance(time.Hour)
+	client.sweep()
+
+	assert.Equal(t, 0, client.Stats().Entries)
+}
+
+func TestCachedClientClose(t *testing.T) {
+	t.Run("Without sweeper", func(t *testing.T) {
+		next := &closeCounter{}
+		client := NewCachedClient(next, CacheOptions{})
+
+		assert.NoError(t, client.Close())
+		assert.Equal(t, 1, next.closed)
+	})
+
+	t.Run("Stops the sweeper", func(t *testing.T) {
+		next := &closeCounter{}
+		client := NewCachedClient(next, CacheOptions{SweepInterval: time.Millisecond})
+
+		assert.NoError(t, client.Close())
+		assert.NoError(t, client.Close())
+		assert.Equal(t, 2, next.closed)
+
+		select {
+		case <-client.stopped:
+		default:
+			assert.Fail(t, "sweeper still running")
+		}
+	})
+
+	t.Run("Through the client stack", func(t *testing.T) {
+		next := &closeCounter{}
+
+		assert.NoError(t, Close(NewCachedClient(NewCoalescingClient(next), CacheOptions{})))
+		assert.NoError(t, Close(&MockClient{}))
+		assert.Equal(t, 1, next.closed)
+	})
+}
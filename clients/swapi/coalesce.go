@@ -0,0 +1,262 @@
+package swapi
+
+import (
+	"context"
+	"fmt"
+	"swapi/errors"
+	"swapi/models"
+	"sync"
+	"time"
+)
+
+type inflightCall struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+	// waiters counts the callers waiting for the call, which is canceled once
+	// they all gave up
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// CoalescingClient is a Client decorator that shares one upstream call, and
+// its result or error, between every concurrent caller asking for the same
+// resource
+type CoalescingClient struct {
+	next Client
+
+	mu    sync.Mutex
+	calls map[string]*inflightCall
+}
+
+func NewCoalescingClient(next Client) *CoalescingClient {
+	return &CoalescingClient{
+		next:  next,
+		calls: map[string]*inflightCall{},
+	}
+}
+
+func (c *CoalescingClient) Diagnostics() Diagnostics {
+	return Diagnose(c.next)
+}
+
+func (c *CoalescingClient) Close() error {
+	return Close(c.next)
+}
+
+func (c *CoalescingClient) Ping(ctx context.Context) error {
+	return Ping(ctx, c.next)
+}
+
+func (c *CoalescingClient) GetStarship(ctx context.Context, id int) (models.Starship, error) {
+	return coalesced(ctx, c, "starships", fmt.Sprint(id), func(ctx context.Context) (models.Starship, error) {
+		return c.next.GetStarship(ctx, id)
+	})
+}
+
+func (c *CoalescingClient) GetStarships(ctx context.Context, page int) (models.Starships, error) {
+	return coalesced(ctx, c, "starships", fmt.Sprintf("?page=%d", page), func(ctx context.Context) (models.Starships, error) {
+		return c.next.GetStarships(ctx, page)
+	})
+}
+
+func (c *CoalescingClient) GetAllStarships(ctx context.Context) (models.Starships, error) {
+	return coalesced(ctx, c, "starships", "?all", func(ctx context.Context) (models.Starships, error) {
+		return c.next.GetAllStarships(ctx)
+	})
+}
+
+func (c *CoalescingClient) SearchStarships(ctx context.Context, query string) (models.Starships, error) {
+	return coalesced(ctx, c, "starships", "?search="+query, func(ctx context.Context) (models.Starships, error) {
+		return c.next.SearchStarships(ctx, query)
+	})
+}
+
+func (c *CoalescingClient) GetPeople(ctx context.Context, id int) (models.People, error) {
+	return coalesced(ctx, c, "people", fmt.Sprint(id), func(ctx context.Context) (models.People, error) {
+		return c.next.GetPeople(ctx, id)
+	})
+}
+
+func (c *CoalescingClient) GetPeopleList(ctx context.Context, page int) (models.PeopleList, error) {
+	return coalesced(ctx, c, "people", fmt.Sprintf("?page=%d", page), func(ctx context.Context) (models.PeopleList, error) {
+		return c.next.GetPeopleList(ctx, page)
+	})
+}
+
+func (c *CoalescingClient) GetAllPeople(ctx context.Context) (models.PeopleList, error) {
+	return coalesced(ctx, c, "people", "?all", func(ctx context.Context) (models.PeopleList, error) {
+		return c.next.GetAllPeople(ctx)
+	})
+}
+
+func (c *CoalescingClient) SearchPeople(ctx context.Context, query string) (models.PeopleList, error) {
+	return coalesced(ctx, c, "people", "?search="+query, func(ctx context.Context) (models.PeopleList, error) {
+		return c.next.SearchPeople(ctx, query)
+	})
+}
+
+func (c *CoalescingClient) GetFilm(ctx context.Context, id int) (models.Film, error) {
+	return coalesced(ctx, c, "films", fmt.Sprint(id), func(ctx context.Context) (models.Film, error) {
+		return c.next.GetFilm(ctx, id)
+	})
+}
+
+func (c *CoalescingClient) GetFilms(ctx context.Context, page int) (models.Films, error) {
+	return coalesced(ctx, c, "films", fmt.Sprintf("?page=%d", page), func(ctx context.Context) (models.Films, error) {
+		return c.next.GetFilms(ctx, page)
+	})
+}
+
+func (c *CoalescingClient) GetAllFilms(ctx context.Context) (models.Films, error) {
+	return coalesced(ctx, c, "films", "?all", func(ctx context.Context) (models.Films, error) {
+		return c.next.GetAllFilms(ctx)
+	})
+}
+
+func (c *CoalescingClient) GetPlanet(ctx context.Context, id int) (models.Planet, error) {
+	return coalesced(ctx, c, "planets", fmt.Sprint(id), func(ctx context.Context) (models.Planet, error) {
+		return c.next.GetPlanet(ctx, id)
+	})
+}
+
+func (c *CoalescingClient) GetPlanets(ctx context.Context, page int) (models.Planets, error) {
+	return coalesced(ctx, c, "planets", fmt.Sprintf("?page=%d", page), func(ctx context.Context) (models.Planets, error) {
+		return c.next.GetPlanets(ctx, page)
+	})
+}
+
+func (c *CoalescingClient) GetAllPlanets(ctx context.Context) (models.Planets, error) {
+	return coalesced(ctx, c, "planets", "?all", func(ctx context.Context) (models.Planets, error) {
+		return c.next.GetAllPlanets(ctx)
+	})
+}
+
+func (c *CoalescingClient) GetSpecies(ctx context.Context, id int) (models.Species, error) {
+	return coalesced(ctx, c, "species", fmt.Sprint(id), func(ctx context.Context) (models.Species, error) {
+		return c.next.GetSpecies(ctx, id)
+	})
+}
+
+func (c *CoalescingClient) GetSpeciesList(ctx context.Context, page int) (models.SpeciesList, error) {
+	return coalesced(ctx, c, "species", fmt.Sprintf("?page=%d", page), func(ctx context.Context) (models.SpeciesList, error) {
+		return c.next.GetSpeciesList(ctx, page)
+	})
+}
+
+func (c *CoalescingClient) GetAllSpecies(ctx context.Context) (models.SpeciesList, error) {
+	return coalesced(ctx, c, "species", "?all", func(ctx context.Context) (models.SpeciesList, error) {
+		return c.next.GetAllSpecies(ctx)
+	})
+}
+
+func (c *CoalescingClient) GetVehicle(ctx context.Context, id int) (models.Vehicle, error) {
+	return coalesced(ctx, c, "vehicles", fmt.Sprint(id), func(ctx context.Context) (models.Vehicle, error) {
+		return c.next.GetVehicle(ctx, id)
+	})
+}
+
+func (c *CoalescingClient) GetVehicles(ctx context.Context, page int) (models.Vehicles, error) {
+	return coalesced(ctx, c, "vehicles", fmt.Sprintf("?page=%d", page), func(ctx context.Context) (models.Vehicles, error) {
+		return c.next.GetVehicles(ctx, page)
+	})
+}
+
+func (c *CoalescingClient) GetAllVehicles(ctx context.Context) (models.Vehicles, error) {
+	return coalesced(ctx, c, "vehicles", "?all", func(ctx context.Context) (models.Vehicles, error) {
+		return c.next.GetAllVehicles(ctx)
+	})
+}
+
+// coalesced runs fetch unless a call for resource/key is already in flight,
+// in which case it waits for that call and returns its result. The shared
+// call runs on a context no single caller can cancel: every caller stops
+// waiting when its own context is done, and the call is only canceled once
+// none of them is left.
+func coalesced[T any](ctx context.Context, c *CoalescingClient, resource string, key string, fetch func(ctx context.Context) (T, error)) (T, error) {
+	key = resource + "/" + key
+
+	c.mu.Lock()
+
+	call, ok := c.calls[key]
+
+	if !ok {
+		// the values of the caller starting the call, such as its request ID
+		// and span, are kept
+		fetchCtx, cancel := context.WithCancel(detached{ctx})
+
+		call = &inflightCall{done: make(chan struct{}), cancel: cancel}
+		c.calls[key] = call
+
+		go func() {
+			var zero T
+			call.value = zero
+
+			defer func() {
+				if r := recover(); r != nil {
+					call.err = errors.NewInternal().WithCause(fmt.Errorf("panic: %v", r))
+				}
+
+				c.mu.Lock()
+				c.forget(key, call)
+				c.mu.Unlock()
+
+				cancel()
+				close(call.done)
+			}()
+
+			call.value, call.err = fetch(fetchCtx)
+		}()
+	}
+
+	call.waiters++
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value.(T), call.err
+	case <-ctx.Done():
+		c.mu.Lock()
+		call.waiters--
+
+		if call.waiters == 0 {
+			call.cancel()
+			c.forget(key, call)
+		}
+
+		c.mu.Unlock()
+
+		var zero T
+		return zero, transportError(ctx.Err())
+	}
+}
+
+// forget removes call from the calls in flight, unless another one already
+// replaced it. c.mu must be held.
+func (c *CoalescingClient) forget(key string, call *inflightCall) {
+	if c.calls[key] == call {
+		delete(c.calls, key)
+	}
+}
+
+// detached keeps the values of its parent, without its deadline and
+// cancellation
+type detached struct {
+	parent context.Context
+}
+
+func (d detached) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (d detached) Done() <-chan struct{} {
+	return nil
+}
+
+func (d detached) Err() error {
+	return nil
+}
+
+func (d detached) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
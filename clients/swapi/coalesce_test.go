@@ -0,0 +1,313 @@
+package swapi
+
+import (
+	"context"
+	"swapi/errors"
+	"swapi/mockeable"
+	"swapi/models"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// callConcurrently runs fn from n goroutines while the upstream call is held
+// open by release, so every caller overlaps with the first one
+func callConcurrently(n int, started chan struct{}, release chan struct{}, fn func()) {
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		fn()
+	}()
+
+	<-started
+
+	for i := 1; i < n; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			fn()
+		}()
+	}
+
+	// give the followers time to find the call in flight
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	wg.Wait()
+}
+
+func TestCoalescingClient(t *testing.T) {
+	t.Run("Concurrent callers share one call", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		swapiMock := MockClient{
+			GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+				close(started)
+				<-release
+
+				return models.People{Name: "Luke Skywalker"}, nil
+			},
+			GetPeopleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+		}
+
+		swapiMock.Use()
+		defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+		client := NewCoalescingClient(&swapiMock)
+
+		var mu sync.Mutex
+		var names []string
+
+		callConcurrently(10, started, release, func() {
+			result, err := client.GetPeople(context.Background(), 1)
+
+			assert.Nil(t, err)
+
+			mu.Lock()
+			names = append(names, result.Name)
+			mu.Unlock()
+		})
+
+		assert.Len(t, names, 10)
+
+		for _, name := range names {
+			assert.Equal(t, "Luke Skywalker", name)
+		}
+	})
+
+	t.Run("Concurrent callers share the error", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		swapiMock := MockClient{
+			GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
+				close(started)
+				<-release
+
+				return models.Starship{}, errors.NewNotFound("starships", "1")
+			},
+			GetStarshipFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+		}
+
+		swapiMock.Use()
+		defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+		client := NewCoalescingClient(&swapiMock)
+
+		callConcurrently(5, started, release, func() {
+			_, err := client.GetStarship(context.Background(), 1)
+
+			assert.Equal(t, errors.NewNotFound("starships", "1"), err)
+		})
+	})
+
+	t.Run("Waiting caller gives up with its context", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		swapiMock := MockClient{
+			GetPlanetFunc: func(ctx context.Context, id int) (models.Planet, error) {
+				close(started)
+				<-release
+
+				return models.Planet{Name: "Tatooine"}, nil
+			},
+			GetPlanetFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+		}
+
+		swapiMock.Use()
+		defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+		client := NewCoalescingClient(&swapiMock)
+
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			result, err := client.GetPlanet(context.Background(), 1)
+
+			assert.Nil(t, err)
+			assert.Equal(t, "Tatooine", result.Name)
+		}()
+
+		<-started
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := client.GetPlanet(ctx, 1)
+
+		assert.ErrorIs(t, err, context.Canceled)
+
+		close(release)
+		<-done
+	})
+
+	t.Run("Leader canceling doesn't fail the others", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		swapiMock := MockClient{
+			GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+				close(started)
+
+				select {
+				case <-release:
+					return models.People{Name: "Luke Skywalker"}, nil
+				case <-ctx.Done():
+					return models.People{}, ctx.Err()
+				}
+			},
+			GetPeopleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+		}
+
+		swapiMock.Use()
+		defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+		client := NewCoalescingClient(&swapiMock)
+
+		leaderCtx, cancelLeader := context.WithCancel(context.Background())
+		leaderDone := make(chan error)
+
+		go func() {
+			_, err := client.GetPeople(leaderCtx, 1)
+			leaderDone <- err
+		}()
+
+		<-started
+
+		followerDone := make(chan struct{})
+
+		go func() {
+			defer close(followerDone)
+
+			result, err := client.GetPeople(context.Background(), 1)
+
+			assert.Nil(t, err)
+			assert.Equal(t, "Luke Skywalker", result.Name)
+		}()
+
+		// give the follower time to find the call in flight
+		time.Sleep(50 * time.Millisecond)
+		cancelLeader()
+
+		assert.ErrorIs(t, <-leaderDone, context.Canceled)
+
+		close(release)
+		<-followerDone
+	})
+
+	t.Run("Waiting caller's deadline is a gateway timeout", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		swapiMock := MockClient{
+			GetVehicleFunc: func(ctx context.Context, id int) (models.Vehicle, error) {
+				close(started)
+				<-release
+
+				return models.Vehicle{}, nil
+			},
+			GetVehicleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+		}
+
+		swapiMock.Use()
+		defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+		client := NewCoalescingClient(&swapiMock)
+
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+			client.GetVehicle(context.Background(), 1)
+		}()
+
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := client.GetVehicle(ctx, 1)
+
+		assert.ErrorIs(t, err, errors.NewGatewayTimeout())
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		close(release)
+		<-done
+	})
+
+	t.Run("Call is canceled once every caller gave up", func(t *testing.T) {
+		canceled := make(chan struct{})
+
+		swapiMock := MockClient{
+			GetSpeciesFunc: func(ctx context.Context, id int) (models.Species, error) {
+				<-ctx.Done()
+				close(canceled)
+
+				return models.Species{}, ctx.Err()
+			},
+			GetSpeciesFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+		}
+
+		swapiMock.Use()
+		defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+		client := NewCoalescingClient(&swapiMock)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := client.GetSpecies(ctx, 1)
+
+		assert.ErrorIs(t, err, errors.NewGatewayTimeout())
+
+		select {
+		case <-canceled:
+		case <-time.After(time.Second):
+			t.Fatal("shared call was not canceled")
+		}
+	})
+
+	t.Run("Different ids are not shared", func(t *testing.T) {
+		swapiMock := MockClient{
+			GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+				return models.People{}, nil
+			},
+			GetPeopleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 2},
+		}
+
+		swapiMock.Use()
+		defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+		client := NewCoalescingClient(&swapiMock)
+
+		client.GetPeople(context.Background(), 1)
+		client.GetPeople(context.Background(), 2)
+	})
+
+	t.Run("Finished calls are not reused", func(t *testing.T) {
+		swapiMock := MockClient{
+			GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+				return models.Film{}, nil
+			},
+			GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 2},
+		}
+
+		swapiMock.Use()
+		defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+		client := NewCoalescingClient(&swapiMock)
+
+		client.GetFilm(context.Background(), 1)
+		client.GetFilm(context.Background(), 1)
+	})
+}
@@ -1,15 +1,64 @@
 package swapi
 
-import "swapi/models"
+import (
+	"context"
+	"io"
+	"swapi/models"
+)
 
 type Client interface {
-	GetStarship(id int) (models.Starship, error)
-	GetStarships() (models.Starships, error)
-	GetPeople(id int) (models.People, error)
-	GetPeopleList() (models.PeopleList, error)
+	GetStarship(ctx context.Context, id int) (models.Starship, error)
+	GetStarships(ctx context.Context, page int) (models.Starships, error)
+	GetAllStarships(ctx context.Context) (models.Starships, error)
+	SearchStarships(ctx context.Context, query string) (models.Starships, error)
+	GetPeople(ctx context.Context, id int) (models.People, error)
+	GetPeopleList(ctx context.Context, page int) (models.PeopleList, error)
+	GetAllPeople(ctx context.Context) (models.PeopleList, error)
+	SearchPeople(ctx context.Context, query string) (models.PeopleList, error)
+	GetFilm(ctx context.Context, id int) (models.Film, error)
+	GetFilms(ctx context.Context, page int) (models.Films, error)
+	GetAllFilms(ctx context.Context) (models.Films, error)
+	GetPlanet(ctx context.Context, id int) (models.Planet, error)
+	GetPlanets(ctx context.Context, page int) (models.Planets, error)
+	GetAllPlanets(ctx context.Context) (models.Planets, error)
+	GetSpecies(ctx context.Context, id int) (models.Species, error)
+	GetSpeciesList(ctx context.Context, page int) (models.SpeciesList, error)
+	GetAllSpecies(ctx context.Context) (models.SpeciesList, error)
+	GetVehicle(ctx context.Context, id int) (models.Vehicle, error)
+	GetVehicles(ctx context.Context, page int) (models.Vehicles, error)
+	GetAllVehicles(ctx context.Context) (models.Vehicles, error)
+}
+
+// Diagnoser is implemented by clients that can report on their own state
+type Diagnoser interface {
+	Diagnostics() Diagnostics
+}
+
+type Diagnostics struct {
+	Breaker *BreakerStats `json:"breaker,omitempty"`
+	Cache   *CacheStats   `json:"cache,omitempty"`
+}
+
+// Diagnose returns the diagnostics of c, if it reports any
+func Diagnose(c Client) Diagnostics {
+	if d, ok := c.(Diagnoser); ok {
+		return d.Diagnostics()
+	}
+
+	return Diagnostics{}
+}
+
+// Close releases the resources held by c, such as background workers and idle
+// connections, if it holds any
+func Close(c Client) error {
+	if closer, ok := c.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
 }
 
 var (
-	defaultInstance Client = NewSWAPIClient()
+	defaultInstance Client = NewSWAPIClient(DefaultClientOptions())
 	Instance               = defaultInstance
 )
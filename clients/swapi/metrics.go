@@ -0,0 +1,71 @@
+package swapi
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"strconv"
+	"swapi/errors"
+	"swapi/metrics"
+	"time"
+)
+
+var (
+	upstreamRequests = metrics.Default.NewCounter(
+		"swapi_upstream_requests_total",
+		"Requests sent to SWAPI, retries included, by resource and status code. Requests that got no response have the code \"error\", or \"canceled\" when we canceled them.",
+		"resource", "code",
+	)
+	upstreamDuration = metrics.Default.NewHistogram(
+		"swapi_upstream_request_duration_seconds",
+		"Latency of the requests sent to SWAPI, retries included, by resource.",
+		metrics.DefaultBuckets,
+		"resource",
+	)
+	upstreamErrors = metrics.Default.NewCounter(
+		"swapi_upstream_errors_total",
+		"Failed calls to SWAPI, once retries are exhausted, by resource and error type.",
+		"resource", "type",
+	)
+	cacheRequests = metrics.Default.NewCounter(
+		"swapi_cache_requests_total",
+		"Lookups in the SWAPI response cache by resource and result, hit or miss.",
+		"resource", "result",
+	)
+)
+
+// observeAttempt records a single request sent to SWAPI
+func observeAttempt(resource string, res *http.Response, err error, start time.Time) {
+	code := "error"
+
+	switch {
+	case res != nil:
+		code = strconv.Itoa(res.StatusCode)
+	case stderrors.Is(err, context.Canceled):
+		code = "canceled"
+	}
+
+	upstreamRequests.Inc(resource, code)
+	upstreamDuration.Observe(time.Since(start).Seconds(), resource)
+}
+
+// observeError records a failed call to SWAPI by error type: malformed bodies
+// are upstream contract violations and transport failures bad gateways or
+// gateway timeouts, only untyped errors are counted as internal ones. Calls we
+// canceled ourselves, for a client that went away or a collection that
+// already failed, say nothing about SWAPI and aren't counted.
+func observeError(resource string, err error) {
+	if err == nil || stderrors.Is(err, context.Canceled) {
+		return
+	}
+
+	errorType := errors.Internal
+
+	var e *errors.Error
+
+	if stderrors.As(err, &e) {
+		errorType = e.Type
+	}
+
+	upstreamErrors.Inc(resource, string(errorType))
+}
@@ -0,0 +1,88 @@
+package swapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"swapi/errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/vehicles/4/":
+			// fails once, then succeeds on retry
+			if atomic.AddInt32(&calls, 1) == 1 {
+				rw.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			fmt.Fprint(rw, `{"name":"Sand Crawler"}`)
+		case "/vehicles/5/":
+			fmt.Fprint(rw, `{"name":`)
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+
+	client := newRetryTestClient(server, DefaultRetryPolicy(), &waits)
+
+	ok := upstreamRequests.Value("vehicles", "200")
+	unavailable := upstreamRequests.Value("vehicles", "503")
+	notFound := upstreamRequests.Value("vehicles", "404")
+	latencies := upstreamDuration.Count("vehicles")
+	notFoundErrors := upstreamErrors.Value("vehicles", string(errors.NotFound))
+	contractViolations := upstreamErrors.Value("vehicles", string(errors.UpstreamContractViolation))
+
+	_, err := client.GetVehicle(context.Background(), 4)
+	assert.Nil(t, err)
+
+	_, err = client.GetVehicle(context.Background(), 5)
+	assert.NotNil(t, err, "malformed body")
+
+	_, err = client.GetVehicle(context.Background(), 99)
+	assert.Equal(t, errors.NewNotFound("vehicles", "99"), err)
+
+	assert.Equal(t, ok+2, upstreamRequests.Value("vehicles", "200"))
+	assert.Equal(t, unavailable+1, upstreamRequests.Value("vehicles", "503"))
+	assert.Equal(t, notFound+1, upstreamRequests.Value("vehicles", "404"))
+	assert.Equal(t, latencies+4, upstreamDuration.Count("vehicles"))
+	assert.Equal(t, notFoundErrors+1, upstreamErrors.Value("vehicles", string(errors.NotFound)))
+	assert.Equal(t, contractViolations+1, upstreamErrors.Value("vehicles", string(errors.UpstreamContractViolation)))
+}
+
+func TestMetricsCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, `{"name":"Millennium Falcon"}`)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+
+	client := newRetryTestClient(server, DefaultRetryPolicy(), &waits)
+
+	canceled := upstreamRequests.Value("starships", "canceled")
+	failed := upstreamRequests.Value("starships", "error")
+	internalErrors := upstreamErrors.Value("starships", string(errors.Internal))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.GetStarship(ctx, 10)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	assert.Equal(t, canceled+1, upstreamRequests.Value("starships", "canceled"))
+	assert.Equal(t, failed, upstreamRequests.Value("starships", "error"))
+	assert.Equal(t, internalErrors, upstreamErrors.Value("starships", string(errors.Internal)), "not an upstream error")
+}
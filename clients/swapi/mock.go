@@ -1,51 +1,196 @@
 package swapi
 
 import (
+	"context"
 	"swapi/mockeable"
 	"swapi/models"
 )
 
 type MockClient struct {
-	GetStarshipFunc   func(id int) (models.Starship, error)
-	GetStarshipsFunc  func() (models.Starships, error)
-	GetPeopleFunc     func(id int) (models.People, error)
-	GetPeopleListFunc func() (models.PeopleList, error)
+	GetStarshipFunc     func(ctx context.Context, id int) (models.Starship, error)
+	GetStarshipsFunc    func(ctx context.Context, page int) (models.Starships, error)
+	GetAllStarshipsFunc func(ctx context.Context) (models.Starships, error)
+	SearchStarshipsFunc func(ctx context.Context, query string) (models.Starships, error)
+	GetPeopleFunc       func(ctx context.Context, id int) (models.People, error)
+	GetPeopleListFunc   func(ctx context.Context, page int) (models.PeopleList, error)
+	GetAllPeopleFunc    func(ctx context.Context) (models.PeopleList, error)
+	SearchPeopleFunc    func(ctx context.Context, query string) (models.PeopleList, error)
+	GetFilmFunc         func(ctx context.Context, id int) (models.Film, error)
+	GetFilmsFunc        func(ctx context.Context, page int) (models.Films, error)
+	GetAllFilmsFunc     func(ctx context.Context) (models.Films, error)
+	GetPlanetFunc       func(ctx context.Context, id int) (models.Planet, error)
+	GetPlanetsFunc      func(ctx context.Context, page int) (models.Planets, error)
+	GetAllPlanetsFunc   func(ctx context.Context) (models.Planets, error)
+	GetSpeciesFunc      func(ctx context.Context, id int) (models.Species, error)
+	GetSpeciesListFunc  func(ctx context.Context, page int) (models.SpeciesList, error)
+	GetAllSpeciesFunc   func(ctx context.Context) (models.SpeciesList, error)
+	GetVehicleFunc      func(ctx context.Context, id int) (models.Vehicle, error)
+	GetVehiclesFunc     func(ctx context.Context, page int) (models.Vehicles, error)
+	GetAllVehiclesFunc  func(ctx context.Context) (models.Vehicles, error)
 
-	GetStarshipFuncControl   mockeable.CallsFuncControl
-	GetStarshipsFuncControl  mockeable.CallsFuncControl
-	GetPeopleFuncControl     mockeable.CallsFuncControl
-	GetPeopleListFuncControl mockeable.CallsFuncControl
+	GetStarshipFuncControl     mockeable.CallsFuncControl
+	GetStarshipsFuncControl    mockeable.CallsFuncControl
+	GetAllStarshipsFuncControl mockeable.CallsFuncControl
+	SearchStarshipsFuncControl mockeable.CallsFuncControl
+	GetPeopleFuncControl       mockeable.CallsFuncControl
+	GetPeopleListFuncControl   mockeable.CallsFuncControl
+	GetAllPeopleFuncControl    mockeable.CallsFuncControl
+	SearchPeopleFuncControl    mockeable.CallsFuncControl
+	GetFilmFuncControl         mockeable.CallsFuncControl
+	GetFilmsFuncControl        mockeable.CallsFuncControl
+	GetAllFilmsFuncControl     mockeable.CallsFuncControl
+	GetPlanetFuncControl       mockeable.CallsFuncControl
+	GetPlanetsFuncControl      mockeable.CallsFuncControl
+	GetAllPlanetsFuncControl   mockeable.CallsFuncControl
+	GetSpeciesFuncControl      mockeable.CallsFuncControl
+	GetSpeciesListFuncControl  mockeable.CallsFuncControl
+	GetAllSpeciesFuncControl   mockeable.CallsFuncControl
+	GetVehicleFuncControl      mockeable.CallsFuncControl
+	GetVehiclesFuncControl     mockeable.CallsFuncControl
+	GetAllVehiclesFuncControl  mockeable.CallsFuncControl
 }
 
-func (c *MockClient) GetStarship(id int) (models.Starship, error) {
+func (c *MockClient) GetStarship(ctx context.Context, id int) (models.Starship, error) {
 	c.GetStarshipFuncControl.IncreaseCallCount()
 
-	return c.GetStarshipFunc(id)
+	return c.GetStarshipFunc(ctx, id)
 }
 
-func (c *MockClient) GetStarships() (models.Starships, error) {
+func (c *MockClient) GetStarships(ctx context.Context, page int) (models.Starships, error) {
 	c.GetStarshipsFuncControl.IncreaseCallCount()
 
-	return c.GetStarshipsFunc()
+	return c.GetStarshipsFunc(ctx, page)
 }
 
-func (c *MockClient) GetPeople(id int) (models.People, error) {
+func (c *MockClient) GetAllStarships(ctx context.Context) (models.Starships, error) {
+	c.GetAllStarshipsFuncControl.IncreaseCallCount()
+
+	return c.GetAllStarshipsFunc(ctx)
+}
+
+func (c *MockClient) SearchStarships(ctx context.Context, query string) (models.Starships, error) {
+	c.SearchStarshipsFuncControl.IncreaseCallCount()
+
+	return c.SearchStarshipsFunc(ctx, query)
+}
+
+func (c *MockClient) GetPeople(ctx context.Context, id int) (models.People, error) {
 	c.GetPeopleFuncControl.IncreaseCallCount()
 
-	return c.GetPeopleFunc(id)
+	return c.GetPeopleFunc(ctx, id)
 }
 
-func (c *MockClient) GetPeopleList() (models.PeopleList, error) {
+func (c *MockClient) GetPeopleList(ctx context.Context, page int) (models.PeopleList, error) {
 	c.GetPeopleListFuncControl.IncreaseCallCount()
 
-	return c.GetPeopleListFunc()
+	return c.GetPeopleListFunc(ctx, page)
+}
+
+func (c *MockClient) GetAllPeople(ctx context.Context) (models.PeopleList, error) {
+	c.GetAllPeopleFuncControl.IncreaseCallCount()
+
+	return c.GetAllPeopleFunc(ctx)
+}
+
+func (c *MockClient) SearchPeople(ctx context.Context, query string) (models.PeopleList, error) {
+	c.SearchPeopleFuncControl.IncreaseCallCount()
+
+	return c.SearchPeopleFunc(ctx, query)
+}
+
+func (c *MockClient) GetFilm(ctx context.Context, id int) (models.Film, error) {
+	c.GetFilmFuncControl.IncreaseCallCount()
+
+	return c.GetFilmFunc(ctx, id)
+}
+
+func (c *MockClient) GetFilms(ctx context.Context, page int) (models.Films, error) {
+	c.GetFilmsFuncControl.IncreaseCallCount()
+
+	return c.GetFilmsFunc(ctx, page)
+}
+
+func (c *MockClient) GetAllFilms(ctx context.Context) (models.Films, error) {
+	c.GetAllFilmsFuncControl.IncreaseCallCount()
+
+	return c.GetAllFilmsFunc(ctx)
+}
+
+func (c *MockClient) GetPlanet(ctx context.Context, id int) (models.Planet, error) {
+	c.GetPlanetFuncControl.IncreaseCallCount()
+
+	return c.GetPlanetFunc(ctx, id)
+}
+
+func (c *MockClient) GetPlanets(ctx context.Context, page int) (models.Planets, error) {
+	c.GetPlanetsFuncControl.IncreaseCallCount()
+
+	return c.GetPlanetsFunc(ctx, page)
+}
+
+func (c *MockClient) GetAllPlanets(ctx context.Context) (models.Planets, error) {
+	c.GetAllPlanetsFuncControl.IncreaseCallCount()
+
+	return c.GetAllPlanetsFunc(ctx)
+}
+
+func (c *MockClient) GetSpecies(ctx context.Context, id int) (models.Species, error) {
+	c.GetSpeciesFuncControl.IncreaseCallCount()
+
+	return c.GetSpeciesFunc(ctx, id)
+}
+
+func (c *MockClient) GetSpeciesList(ctx context.Context, page int) (models.SpeciesList, error) {
+	c.GetSpeciesListFuncControl.IncreaseCallCount()
+
+	return c.GetSpeciesListFunc(ctx, page)
+}
+
+func (c *MockClient) GetAllSpecies(ctx context.Context) (models.SpeciesList, error) {
+	c.GetAllSpeciesFuncControl.IncreaseCallCount()
+
+	return c.GetAllSpeciesFunc(ctx)
+}
+
+func (c *MockClient) GetVehicle(ctx context.Context, id int) (models.Vehicle, error) {
+	c.GetVehicleFuncControl.IncreaseCallCount()
+
+	return c.GetVehicleFunc(ctx, id)
+}
+
+func (c *MockClient) GetVehicles(ctx context.Context, page int) (models.Vehicles, error) {
+	c.GetVehiclesFuncControl.IncreaseCallCount()
+
+	return c.GetVehiclesFunc(ctx, page)
+}
+
+func (c *MockClient) GetAllVehicles(ctx context.Context) (models.Vehicles, error) {
+	c.GetAllVehiclesFuncControl.IncreaseCallCount()
+
+	return c.GetAllVehiclesFunc(ctx)
 }
 
 func (c *MockClient) Use() {
 	c.GetStarshipFuncControl.SetFuncName("GetStarship")
 	c.GetStarshipsFuncControl.SetFuncName("GetStarships")
+	c.GetAllStarshipsFuncControl.SetFuncName("GetAllStarships")
+	c.SearchStarshipsFuncControl.SetFuncName("SearchStarships")
 	c.GetPeopleFuncControl.SetFuncName("GetPeople")
 	c.GetPeopleListFuncControl.SetFuncName("GetPeopleList")
+	c.GetAllPeopleFuncControl.SetFuncName("GetAllPeople")
+	c.SearchPeopleFuncControl.SetFuncName("SearchPeople")
+	c.GetFilmFuncControl.SetFuncName("GetFilm")
+	c.GetFilmsFuncControl.SetFuncName("GetFilms")
+	c.GetAllFilmsFuncControl.SetFuncName("GetAllFilms")
+	c.GetPlanetFuncControl.SetFuncName("GetPlanet")
+	c.GetPlanetsFuncControl.SetFuncName("GetPlanets")
+	c.GetAllPlanetsFuncControl.SetFuncName("GetAllPlanets")
+	c.GetSpeciesFuncControl.SetFuncName("GetSpecies")
+	c.GetSpeciesListFuncControl.SetFuncName("GetSpeciesList")
+	c.GetAllSpeciesFuncControl.SetFuncName("GetAllSpecies")
+	c.GetVehicleFuncControl.SetFuncName("GetVehicle")
+	c.GetVehiclesFuncControl.SetFuncName("GetVehicles")
+	c.GetAllVehiclesFuncControl.SetFuncName("GetAllVehicles")
 
 	Instance = c
 }
@@ -58,7 +203,23 @@ func (c *MockClient) GetFuncControls() []*mockeable.CallsFuncControl {
 	return []*mockeable.CallsFuncControl{
 		&c.GetStarshipFuncControl,
 		&c.GetStarshipsFuncControl,
+		&c.GetAllStarshipsFuncControl,
+		&c.SearchStarshipsFuncControl,
 		&c.GetPeopleFuncControl,
 		&c.GetPeopleListFuncControl,
+		&c.GetAllPeopleFuncControl,
+		&c.SearchPeopleFuncControl,
+		&c.GetFilmFuncControl,
+		&c.GetFilmsFuncControl,
+		&c.GetAllFilmsFuncControl,
+		&c.GetPlanetFuncControl,
+		&c.GetPlanetsFuncControl,
+		&c.GetAllPlanetsFuncControl,
+		&c.GetSpeciesFuncControl,
+		&c.GetSpeciesListFuncControl,
+		&c.GetAllSpeciesFuncControl,
+		&c.GetVehicleFuncControl,
+		&c.GetVehiclesFuncControl,
+		&c.GetAllVehiclesFuncControl,
 	}
 }
@@ -0,0 +1,82 @@
+package swapi
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"swapi/errors"
+	"sync"
+	"time"
+)
+
+// Pinger is implemented by clients that can tell whether their upstream is
+// reachable
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Ping checks whether the upstream of c is reachable. Clients without an
+// upstream always succeed.
+func Ping(ctx context.Context, c Client) error {
+	if p, ok := c.(Pinger); ok {
+		return p.Ping(ctx)
+	}
+
+	return nil
+}
+
+// lastPing is the result of the latest ping, reused for the client's pingTTL
+type lastPing struct {
+	mu  sync.Mutex
+	at  time.Time
+	err error
+}
+
+// Ping sends a single GET to the upstream root, without retries nor the
+// circuit breaker, and fails on network errors and 5xx responses. Its result
+// is reused for pingTTL so frequent probes don't reach SWAPI every time, and
+// concurrent pings wait for the one in flight.
+func (sw *swapiClient) Ping(ctx context.Context) error {
+	sw.ping.mu.Lock()
+	defer sw.ping.mu.Unlock()
+
+	now := sw.now()
+
+	if !sw.ping.at.IsZero() && now.Sub(sw.ping.at) < sw.pingTTL {
+		return sw.ping.err
+	}
+
+	err := sw.probe(ctx)
+
+	// a ping cut short by its caller says nothing about the upstream
+	if ctx.Err() == nil {
+		sw.ping.at, sw.ping.err = now, err
+	}
+
+	return err
+}
+
+func (sw *swapiClient) probe(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sw.baseURL+"/", nil)
+
+	if err != nil {
+		return errors.NewInternal().WithCause(err)
+	}
+
+	res, err := sw.client.Do(req)
+
+	if err != nil {
+		return transportError(err)
+	}
+
+	// drain the body so the connection can be reused
+	io.Copy(ioutil.Discard, res.Body)
+	res.Body.Close()
+
+	if res.StatusCode >= http.StatusInternalServerError {
+		return statusError(res, sw.now())
+	}
+
+	return nil
+}
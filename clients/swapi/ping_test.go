@@ -0,0 +1,129 @@
+package swapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"swapi/errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPing(t *testing.T) {
+	newPingServer := func(status *int32, calls *int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(calls, 1)
+			rw.WriteHeader(int(atomic.LoadInt32(status)))
+		}))
+	}
+
+	newPingClient := func(server *httptest.Server, ttl time.Duration) (*swapiClient, *clock) {
+		options := DefaultClientOptions()
+		options.BaseURL = server.URL
+		options.PingTTL = ttl
+
+		clk := &clock{current: time.Date(2022, 5, 4, 0, 0, 0, 0, time.UTC)}
+
+		client := NewSWAPIClient(options)
+		client.now = clk.now
+
+		return client, clk
+	}
+
+	t.Run("Reachable", func(t *testing.T) {
+		var status, calls int32 = http.StatusNotFound, 0
+
+		server := newPingServer(&status, &calls)
+		defer server.Close()
+
+		client, _ := newPingClient(server, 0)
+
+		assert.Nil(t, client.Ping(context.Background()), "any answer below 500 means reachable")
+		assert.Equal(t, int32(1), calls)
+	})
+
+	t.Run("Failing", func(t *testing.T) {
+		var status, calls int32 = http.StatusServiceUnavailable, 0
+
+		server := newPingServer(&status, &calls)
+		defer server.Close()
+
+		client, _ := newPingClient(server, 0)
+
+		err := client.Ping(context.Background())
+
+		assert.ErrorIs(t, err, errors.NewBadGateway())
+		assert.Equal(t, "upstream responded with status 503", errors.Cause(err))
+		assert.Equal(t, int32(1), calls, "pings aren't retried")
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		var status, calls int32 = http.StatusOK, 0
+
+		server := newPingServer(&status, &calls)
+		client, _ := newPingClient(server, 0)
+		server.Close()
+
+		assert.ErrorIs(t, client.Ping(context.Background()), errors.NewBadGateway().WithCode(errors.CodeUpstreamUnreachable))
+	})
+
+	t.Run("Result reused for the TTL", func(t *testing.T) {
+		var status, calls int32 = http.StatusOK, 0
+
+		server := newPingServer(&status, &calls)
+		defer server.Close()
+
+		client, clk := newPingClient(server, 5*time.Second)
+
+		assert.Nil(t, client.Ping(context.Background()))
+
+		atomic.StoreInt32(&status, http.StatusInternalServerError)
+		clk.advance(4 * time.Second)
+
+		assert.Nil(t, client.Ping(context.Background()))
+		assert.Equal(t, int32(1), calls)
+
+		clk.advance(time.Second)
+
+		assert.ErrorIs(t, client.Ping(context.Background()), errors.NewBadGateway())
+		assert.Equal(t, int32(2), calls)
+	})
+
+	t.Run("Canceled ping isn't reused", func(t *testing.T) {
+		var status, calls int32 = http.StatusOK, 0
+
+		server := newPingServer(&status, &calls)
+		defer server.Close()
+
+		client, _ := newPingClient(server, time.Minute)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		assert.NotNil(t, client.Ping(ctx))
+		assert.Nil(t, client.Ping(context.Background()))
+	})
+
+	t.Run("Through the client stack", func(t *testing.T) {
+		var status, calls int32 = http.StatusOK, 0
+
+		server := newPingServer(&status, &calls)
+		defer server.Close()
+
+		client, _ := newPingClient(server, 0)
+
+		stack := NewCachedClient(NewCoalescingClient(client), DefaultCacheOptions())
+		defer Close(stack)
+
+		assert.Nil(t, Ping(context.Background(), stack))
+		assert.Nil(t, Ping(context.Background(), stack))
+		assert.Equal(t, int32(2), calls, "pings aren't cached by the response cache")
+	})
+
+	t.Run("Client without upstream", func(t *testing.T) {
+		assert.Nil(t, Ping(context.Background(), &MockClient{}))
+	})
+}
@@ -0,0 +1,162 @@
+package swapi
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"swapi/logging"
+	"swapi/tracing"
+	"time"
+)
+
+type RetryPolicy struct {
+	// MaxAttempts counts the first call too, so 1 disables retries
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, doubled on every
+	// following one up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+	}
+}
+
+// backoff returns how long to wait before retrying after the given attempt,
+// using exponential backoff with equal jitter: half of the delay is fixed and
+// the other half random, so concurrent clients don't retry in lockstep
+func (p RetryPolicy) backoff(attempt int, random func() float64) time.Duration {
+	delay := p.InitialBackoff
+
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+
+		if p.MaxBackoff > 0 && delay >= p.MaxBackoff {
+			delay = p.MaxBackoff
+			break
+		}
+	}
+
+	half := delay / 2
+
+	return half + time.Duration(random()*float64(delay-half))
+}
+
+// retryable tells whether a failed attempt is worth repeating. Every call made
+// by this client is an idempotent GET, so network errors and the transient
+// upstream statuses are retried, unless our own context is already done.
+func retryable(ctx context.Context, res *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfter parses the Retry-After header, given either in seconds or as an
+// HTTP date. It returns 0 when the header is missing or invalid.
+func retryAfter(res *http.Response, now time.Time) time.Duration {
+	if res == nil {
+		return 0
+	}
+
+	value := res.Header.Get("Retry-After")
+
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+
+	return 0
+}
+
+// do sends a GET for resource to rawURL, retrying according to the client's
+// policy. The wait before a retry is the policy backoff or the upstream
+// Retry-After, whichever is longer. A Retry-After longer than MaxBackoff ends
+// the retries.
+func (sw *swapiClient) do(ctx context.Context, rawURL string, resource string) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if id := logging.RequestID(ctx); id != "" {
+			req.Header.Set(logging.RequestIDHeader, id)
+		}
+
+		tracing.Inject(ctx, req.Header)
+		logging.AddUpstreamCall(ctx)
+
+		start := time.Now()
+		res, err := sw.client.Do(req)
+		observeAttempt(resource, res, err, start)
+
+		if attempt >= sw.retry.MaxAttempts || !retryable(ctx, res, err) {
+			return res, err
+		}
+
+		wait := sw.retry.backoff(attempt, rand.Float64)
+
+		if after := retryAfter(res, sw.now()); after > wait {
+			// waiting less than the upstream asked would only hammer it, so
+			// when it asks for more than we can wait, give up and let our
+			// client retry after it instead
+			if sw.retry.MaxBackoff > 0 && after > sw.retry.MaxBackoff {
+				return res, err
+			}
+
+			wait = after
+		}
+
+		if res != nil {
+			// drain the body so the connection can be reused
+			io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		if err := sw.wait(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (sw *swapiClient) wait(ctx context.Context, d time.Duration) error {
+	if sw.sleep != nil {
+		return sw.sleep(ctx, d)
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
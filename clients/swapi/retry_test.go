@@ -0,0 +1,354 @@
+package swapi
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"swapi/errors"
+	"swapi/logging"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newRetryTestClient builds a client against server that records the waits
+// between attempts instead of sleeping
+func newRetryTestClient(server *httptest.Server, policy RetryPolicy, waits *[]time.Duration) *swapiClient {
+	options := DefaultClientOptions()
+	options.BaseURL = server.URL
+	options.Retry = policy
+
+	client := NewSWAPIClient(options)
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return ctx.Err()
+	}
+
+	return client
+}
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+	}
+
+	type TestCase struct {
+		Name                  string
+		Statuses              []int
+		Headers               http.Header
+		ExpectedCalls         int32
+		ExpectedErrorResponse error
+		ExpectedWaits         []time.Duration
+	}
+
+	testCases := []TestCase{
+		{
+			Name:          "Success after transient failures",
+			Statuses:      []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			ExpectedCalls: 3,
+		},
+		{
+			Name:                  "Gives up after max attempts",
+			Statuses:              []int{http.StatusGatewayTimeout, http.StatusGatewayTimeout, http.StatusGatewayTimeout, http.StatusOK},
+			ExpectedCalls:         3,
+			ExpectedErrorResponse: errors.NewBadGateway(),
+		},
+		{
+			Name:                  "Not found is not retried",
+			Statuses:              []int{http.StatusNotFound},
+			ExpectedCalls:         1,
+			ExpectedErrorResponse: errors.NewNotFound("starships", "9"),
+		},
+		{
+			Name:                  "Internal server error is not retried",
+			Statuses:              []int{http.StatusInternalServerError, http.StatusOK},
+			ExpectedCalls:         1,
+			ExpectedErrorResponse: errors.NewBadGateway(),
+		},
+		{
+			Name:          "Retry-After in seconds",
+			Statuses:      []int{http.StatusServiceUnavailable, http.StatusOK},
+			Headers:       http.Header{"Retry-After": []string{"1"}},
+			ExpectedCalls: 2,
+			ExpectedWaits: []time.Duration{time.Second},
+		},
+		{
+			Name:                  "Retry-After beyond max backoff ends the retries",
+			Statuses:              []int{http.StatusTooManyRequests, http.StatusOK},
+			Headers:               http.Header{"Retry-After": []string{"120"}},
+			ExpectedCalls:         1,
+			ExpectedErrorResponse: errors.NewUpstreamRateLimited(0),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var calls int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				call := atomic.AddInt32(&calls, 1)
+				status := tc.Statuses[call-1]
+
+				for key, values := range tc.Headers {
+					rw.Header()[key] = values
+				}
+
+				rw.WriteHeader(status)
+
+				if status == http.StatusOK {
+					fmt.Fprint(rw, `{"name":"Death Star"}`)
+				}
+			}))
+			defer server.Close()
+
+			var waits []time.Duration
+
+			client := newRetryTestClient(server, policy, &waits)
+
+			result, err := client.GetStarship(context.Background(), 9)
+
+			assert.Equal(t, tc.ExpectedCalls, atomic.LoadInt32(&calls))
+			assert.Len(t, waits, int(tc.ExpectedCalls)-1)
+
+			if tc.ExpectedWaits != nil {
+				assert.Equal(t, tc.ExpectedWaits, waits)
+			}
+
+			if tc.ExpectedErrorResponse != nil {
+				assert.ErrorIs(t, err, tc.ExpectedErrorResponse)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, "Death Star", result.Name)
+			}
+		})
+	}
+
+	t.Run("Retry-After is passed on", func(t *testing.T) {
+		clk := &clock{current: time.Date(2022, 5, 4, 12, 0, 0, 0, time.UTC)}
+
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("Retry-After", clk.now().Add(time.Minute).Format(http.TimeFormat))
+			rw.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		var waits []time.Duration
+
+		client := newRetryTestClient(server, policy, &waits)
+		client.now = clk.now
+
+		_, err := client.GetStarship(context.Background(), 9)
+
+		var e *errors.Error
+		assert.True(t, stderrors.As(err, &e))
+		assert.Equal(t, errors.CodeUpstreamRateLimited, e.Code)
+		assert.Equal(t, time.Minute, e.RetryAfter)
+		assert.Empty(t, waits)
+	})
+
+	t.Run("Network errors are retried", func(t *testing.T) {
+		var calls int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				// drop the connection without answering
+				conn, _, _ := rw.(http.Hijacker).Hijack()
+				conn.Close()
+				return
+			}
+
+			fmt.Fprint(rw, `{"name":"Luke Skywalker"}`)
+		}))
+		defer server.Close()
+
+		var waits []time.Duration
+
+		client := newRetryTestClient(server, policy, &waits)
+
+		result, err := client.GetPeople(context.Background(), 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Luke Skywalker", result.Name)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("Attempt timeout", func(t *testing.T) {
+		var calls int32
+
+		release := make(chan struct{})
+
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}))
+		defer server.Close()
+		defer close(release)
+
+		var waits []time.Duration
+
+		client := newRetryTestClient(server, policy, &waits)
+		client.client.Timeout = 20 * time.Millisecond
+
+		_, err := client.GetPeople(context.Background(), 1)
+
+		assert.ErrorIs(t, err, errors.NewGatewayTimeout())
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("Overall timeout", func(t *testing.T) {
+		var calls int32
+
+		release := make(chan struct{})
+
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}))
+		defer server.Close()
+		defer close(release)
+
+		var waits []time.Duration
+
+		client := newRetryTestClient(server, RetryPolicy{MaxAttempts: 10, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 20 * time.Millisecond}, &waits)
+		client.sleep = nil
+		client.client.Timeout = 50 * time.Millisecond
+		client.overallTimeout = 120 * time.Millisecond
+
+		start := time.Now()
+		_, err := client.GetPeople(context.Background(), 1)
+		elapsed := time.Since(start)
+
+		assert.ErrorIs(t, err, errors.NewGatewayTimeout())
+		// ten attempts would take over half a second
+		assert.Less(t, elapsed, 300*time.Millisecond)
+		assert.Less(t, atomic.LoadInt32(&calls), int32(10))
+	})
+
+	t.Run("Context done stops retrying", func(t *testing.T) {
+		var calls int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var waits []time.Duration
+
+		client := newRetryTestClient(server, policy, &waits)
+		client.sleep = func(ctx context.Context, d time.Duration) error {
+			cancel()
+			return ctx.Err()
+		}
+
+		_, err := client.GetPeople(ctx, 1)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+}
+
+func TestRequestContext(t *testing.T) {
+	var calls int32
+	var ids []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		ids = append(ids, r.Header.Get(logging.RequestIDHeader))
+
+		if atomic.AddInt32(&calls, 1) == 1 {
+			rw.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		fmt.Fprint(rw, `{"name":"Luke Skywalker"}`)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+
+	client := newRetryTestClient(server, DefaultRetryPolicy(), &waits)
+
+	ctx, upstreamCalls := logging.WithUpstreamCalls(logging.WithRequestID(context.Background(), "abc123"))
+
+	_, err := client.GetPeople(ctx, 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), *upstreamCalls, "every attempt counts")
+	assert.Equal(t, []string{"abc123", "abc123"}, ids)
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+	}
+
+	type TestCase struct {
+		Name     string
+		Attempt  int
+		Random   float64
+		Expected time.Duration
+	}
+
+	testCases := []TestCase{
+		{Name: "First retry, no jitter", Attempt: 1, Random: 0, Expected: 50 * time.Millisecond},
+		{Name: "First retry, full jitter", Attempt: 1, Random: 1, Expected: 100 * time.Millisecond},
+		{Name: "Doubles", Attempt: 3, Random: 1, Expected: 400 * time.Millisecond},
+		{Name: "Capped", Attempt: 10, Random: 1, Expected: time.Second},
+		{Name: "Capped, half jitter", Attempt: 10, Random: 0.5, Expected: 750 * time.Millisecond},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			random := func() float64 { return tc.Random }
+
+			assert.Equal(t, tc.Expected, policy.backoff(tc.Attempt, random))
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2022, 5, 4, 12, 0, 0, 0, time.UTC)
+
+	type TestCase struct {
+		Name     string
+		Value    string
+		Expected time.Duration
+	}
+
+	testCases := []TestCase{
+		{Name: "Missing", Value: "", Expected: 0},
+		{Name: "Seconds", Value: "30", Expected: 30 * time.Second},
+		{Name: "Date", Value: now.Add(time.Minute).Format(http.TimeFormat), Expected: time.Minute},
+		{Name: "Past date", Value: now.Add(-time.Minute).Format(http.TimeFormat), Expected: 0},
+		{Name: "Invalid", Value: "soon", Expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			res := &http.Response{Header: http.Header{}}
+
+			if tc.Value != "" {
+				res.Header.Set("Retry-After", tc.Value)
+			}
+
+			assert.Equal(t, tc.Expected, retryAfter(res, now))
+		})
+	}
+}
@@ -1,138 +1,449 @@
 package swapi
 
 import (
+	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
 	"swapi/errors"
 	"swapi/models"
+	"swapi/tracing"
+	"sync"
+	"time"
 )
 
-func NewSWAPIClient() *swapiClient {
-	return &swapiClient{
-		client:  &http.Client{},
-		baseURL: "https://swapi.dev/api",
+// PageSize is the fixed number of records SWAPI returns per list page
+const PageSize = 10
+
+// maxConcurrentPages bounds how many upstream pages are fetched at once
+// when aggregating a full collection
+const maxConcurrentPages = 4
+
+type ClientOptions struct {
+	BaseURL string
+	// ConnectTimeout bounds dialing the upstream
+	ConnectTimeout time.Duration
+	// Timeout bounds a single attempt, from dialing to reading the body
+	Timeout time.Duration
+	// OverallTimeout bounds a whole call, retries and the waits between them
+	// included, 0 leaves it unbounded
+	OverallTimeout time.Duration
+	Retry          RetryPolicy
+	Breaker        BreakerOptions
+	// PingTTL is how long the result of Ping is reused, 0 disables reuse
+	PingTTL time.Duration
+}
+
+func DefaultClientOptions() ClientOptions {
+	return ClientOptions{
+		BaseURL:        "https://swapi.dev/api",
+		ConnectTimeout: 3 * time.Second,
+		Timeout:        10 * time.Second,
+		OverallTimeout: 15 * time.Second,
+		Retry:          DefaultRetryPolicy(),
+		Breaker:        DefaultBreakerOptions(),
+		PingTTL:        5 * time.Second,
 	}
 }
 
+func NewSWAPIClient(options ClientOptions) *swapiClient {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   options.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+
+	client := &swapiClient{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   options.Timeout,
+		},
+		baseURL:        options.BaseURL,
+		overallTimeout: options.OverallTimeout,
+		retry:          options.Retry,
+		pingTTL:        options.PingTTL,
+		now:            time.Now,
+	}
+
+	if options.Breaker.FailureThreshold > 0 {
+		client.breaker = NewCircuitBreaker(options.Breaker)
+	}
+
+	return client
+}
+
 type swapiClient struct {
-	client  *http.Client
-	baseURL string
+	client         *http.Client
+	baseURL        string
+	overallTimeout time.Duration
+	retry          RetryPolicy
+	breaker        *CircuitBreaker
+	// sleep replaces the wait between retries in tests
+	sleep func(ctx context.Context, d time.Duration) error
+
+	pingTTL time.Duration
+	now     func() time.Time
+	ping    lastPing
 }
 
-func (sw *swapiClient) GetStarship(id int) (result models.Starship, err error) {
-	resource := fmt.Sprintf("/starships/%d/", id)
-	res, err := sw.client.Get(sw.baseURL + resource)
+func (sw *swapiClient) GetStarship(ctx context.Context, id int) (result models.Starship, err error) {
+	err = sw.get(ctx, "starships", fmt.Sprintf("%d", id), &result)
 
-	if err != nil {
-		return result, err
+	return result, err
+}
+
+func (sw *swapiClient) GetStarships(ctx context.Context, page int) (result models.Starships, err error) {
+	err = sw.getPage(ctx, "starships", page, &result)
+
+	return result, err
+}
+
+func (sw *swapiClient) GetAllStarships(ctx context.Context) (result models.Starships, err error) {
+	result.Results, err = getAll[models.Starship](ctx, sw, "starships", "")
+	result.Count = len(result.Results)
+
+	return result, err
+}
+
+func (sw *swapiClient) SearchStarships(ctx context.Context, query string) (result models.Starships, err error) {
+	result.Results, err = getAll[models.Starship](ctx, sw, "starships", query)
+	result.Count = len(result.Results)
+
+	return result, err
+}
+
+func (sw *swapiClient) GetPeople(ctx context.Context, id int) (result models.People, err error) {
+	err = sw.get(ctx, "people", fmt.Sprintf("%d", id), &result)
+
+	return result, err
+}
+
+func (sw *swapiClient) GetPeopleList(ctx context.Context, page int) (result models.PeopleList, err error) {
+	err = sw.getPage(ctx, "people", page, &result)
+
+	return result, err
+}
+
+func (sw *swapiClient) GetAllPeople(ctx context.Context) (result models.PeopleList, err error) {
+	result.Results, err = getAll[models.People](ctx, sw, "people", "")
+	result.Count = len(result.Results)
+
+	return result, err
+}
+
+func (sw *swapiClient) SearchPeople(ctx context.Context, query string) (result models.PeopleList, err error) {
+	result.Results, err = getAll[models.People](ctx, sw, "people", query)
+	result.Count = len(result.Results)
+
+	return result, err
+}
+
+func (sw *swapiClient) GetFilm(ctx context.Context, id int) (result models.Film, err error) {
+	err = sw.get(ctx, "films", fmt.Sprintf("%d", id), &result)
+
+	return result, err
+}
+
+func (sw *swapiClient) GetFilms(ctx context.Context, page int) (result models.Films, err error) {
+	err = sw.getPage(ctx, "films", page, &result)
+
+	return result, err
+}
+
+func (sw *swapiClient) GetAllFilms(ctx context.Context) (result models.Films, err error) {
+	result.Results, err = getAll[models.Film](ctx, sw, "films", "")
+	result.Count = len(result.Results)
+
+	return result, err
+}
+
+func (sw *swapiClient) GetPlanet(ctx context.Context, id int) (result models.Planet, err error) {
+	err = sw.get(ctx, "planets", fmt.Sprintf("%d", id), &result)
+
+	return result, err
+}
+
+func (sw *swapiClient) GetPlanets(ctx context.Context, page int) (result models.Planets, err error) {
+	err = sw.getPage(ctx, "planets", page, &result)
+
+	return result, err
+}
+
+func (sw *swapiClient) GetAllPlanets(ctx context.Context) (result models.Planets, err error) {
+	result.Results, err = getAll[models.Planet](ctx, sw, "planets", "")
+	result.Count = len(result.Results)
+
+	return result, err
+}
+
+func (sw *swapiClient) GetSpecies(ctx context.Context, id int) (result models.Species, err error) {
+	err = sw.get(ctx, "species", fmt.Sprintf("%d", id), &result)
+
+	return result, err
+}
+
+func (sw *swapiClient) GetSpeciesList(ctx context.Context, page int) (result models.SpeciesList, err error) {
+	err = sw.getPage(ctx, "species", page, &result)
+
+	return result, err
+}
+
+func (sw *swapiClient) GetAllSpecies(ctx context.Context) (result models.SpeciesList, err error) {
+	result.Results, err = getAll[models.Species](ctx, sw, "species", "")
+	result.Count = len(result.Results)
+
+	return result, err
+}
+
+func (sw *swapiClient) GetVehicle(ctx context.Context, id int) (result models.Vehicle, err error) {
+	err = sw.get(ctx, "vehicles", fmt.Sprintf("%d", id), &result)
+
+	return result, err
+}
+
+func (sw *swapiClient) GetVehicles(ctx context.Context, page int) (result models.Vehicles, err error) {
+	err = sw.getPage(ctx, "vehicles", page, &result)
+
+	return result, err
+}
+
+func (sw *swapiClient) GetAllVehicles(ctx context.Context) (result models.Vehicles, err error) {
+	result.Results, err = getAll[models.Vehicle](ctx, sw, "vehicles", "")
+	result.Count = len(result.Results)
+
+	return result, err
+}
+
+// page is the envelope SWAPI wraps every list response in
+type page[T any] struct {
+	Count   int    `json:"count"`
+	Next    string `json:"next"`
+	Results []T    `json:"results"`
+}
+
+// getAll fetches every page of a resource, optionally narrowed by a search
+// term. The first page tells us the total count and page size, so the
+// remaining pages are requested concurrently instead of walking the next
+// links one by one.
+func getAll[T any](ctx context.Context, sw *swapiClient, resource string, search string) ([]T, error) {
+	var first page[T]
+
+	if err := sw.searchPage(ctx, resource, 1, search, &first); err != nil {
+		return nil, err
 	}
 
-	if res.StatusCode != http.StatusOK {
-		if res.StatusCode == http.StatusNotFound {
-			return result, errors.NewNotFound("starships", fmt.Sprintf("%d", id))
-		} else {
-			return result, errors.NewInternal()
+	if first.Next == "" || len(first.Results) == 0 {
+		return first.Results, nil
+	}
+
+	pageSize := len(first.Results)
+	pages := (first.Count + pageSize - 1) / pageSize
+
+	// a failed page makes the whole collection fail, so stop the others
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rest := make([]page[T], pages-1)
+	sem := make(chan struct{}, maxConcurrentPages)
+
+	// only the first failure is kept: it's recorded before the other pages
+	// are canceled, so their "context canceled" errors never hide it
+	var (
+		wg       sync.WaitGroup
+		failOnce sync.Once
+		failure  error
+	)
+
+	// pages aren't started once the collection failed, they would only be
+	// canceled attempts in the metrics, logs and traces
+pages:
+	for i := range rest {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break pages
 		}
+
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			if err := sw.searchPage(ctx, resource, i+2, search, &rest[i]); err != nil {
+				failOnce.Do(func() { failure = err })
+				cancel()
+			}
+		}(i)
 	}
 
-	err = getBody(res, &result)
+	wg.Wait()
 
-	if err != nil {
-		return result, err
+	if failure == nil && ctx.Err() != nil {
+		// the caller gave up before every page was fetched
+		failure = transportError(ctx.Err())
 	}
 
-	return result, err
-}
+	if failure != nil {
+		return nil, failure
+	}
 
-func (sw *swapiClient) GetStarships() (result models.Starships, err error) {
-	resource := "/starships/"
-	res, err := sw.client.Get(sw.baseURL + resource)
+	results := first.Results
 
-	if err != nil {
-		return result, err
+	for i := range rest {
+		results = append(results, rest[i].Results...)
 	}
 
-	if res.StatusCode != http.StatusOK {
-		if res.StatusCode == http.StatusNotFound {
-			return result, errors.NewNotFound("starships", "")
-		} else {
-			return result, errors.NewInternal()
-		}
+	return results, nil
+}
+
+// get fetches /{resource}/ or /{resource}/{id}/ and decodes the body into v
+func (sw *swapiClient) get(ctx context.Context, resource string, id string, v interface{}) error {
+	path := fmt.Sprintf("/%s/", resource)
+
+	if id != "" {
+		path = fmt.Sprintf("/%s/%s/", resource, id)
 	}
 
-	err = getBody(res, &result)
+	return sw.fetch(ctx, sw.baseURL+path, resource, id, v)
+}
 
-	if err != nil {
-		return result, err
+// getPage fetches a single page of a resource list
+func (sw *swapiClient) getPage(ctx context.Context, resource string, page int, v interface{}) error {
+	return sw.searchPage(ctx, resource, page, "", v)
+}
+
+// searchPage fetches a single page of a resource list filtered by SWAPI's
+// search parameter, which is left out when empty
+func (sw *swapiClient) searchPage(ctx context.Context, resource string, page int, search string, v interface{}) error {
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+
+	if search != "" {
+		query.Set("search", search)
 	}
 
-	return result, err
+	return sw.fetch(ctx, fmt.Sprintf("%s/%s/?%s", sw.baseURL, resource, query.Encode()), resource, "", v)
 }
 
-func (sw *swapiClient) GetPeople(id int) (result models.People, err error) {
-	resource := fmt.Sprintf("/people/%d/", id)
-	res, err := sw.client.Get(sw.baseURL + resource)
+func (sw *swapiClient) Diagnostics() Diagnostics {
+	stats := sw.breaker.Stats()
 
-	if err != nil {
-		return result, err
+	return Diagnostics{Breaker: &stats}
+}
+
+func (sw *swapiClient) Close() error {
+	sw.client.CloseIdleConnections()
+
+	return nil
+}
+
+func (sw *swapiClient) fetch(ctx context.Context, rawURL string, resource string, id string, v interface{}) (err error) {
+	ctx, span := tracing.Start(ctx, tracing.KindClient, "swapi GET /"+resource, tracing.Attributes{
+		"http.method":    http.MethodGet,
+		"http.url":       rawURL,
+		"swapi.resource": resource,
+	})
+
+	if n, err := strconv.Atoi(id); err == nil {
+		span.SetAttributes(tracing.Attributes{"swapi.id": n})
 	}
 
-	if res.StatusCode != http.StatusOK {
-		if res.StatusCode == http.StatusNotFound {
-			return result, errors.NewNotFound("people", fmt.Sprintf("%d", id))
-		} else {
-			return result, errors.NewInternal()
-		}
+	defer func() {
+		observeError(resource, err)
+		span.End(err)
+	}()
+
+	if !sw.breaker.allow() {
+		return errors.NewUpstreamUnavailable()
 	}
 
-	err = getBody(res, &result)
+	// the caller's context tells the breaker whether the call was abandoned,
+	// running out of our own overall timeout is the upstream's fault
+	callCtx := ctx
 
-	if err != nil {
-		return result, err
+	if sw.overallTimeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, sw.overallTimeout)
+		defer cancel()
 	}
 
-	return result, err
-}
+	res, err := sw.do(callCtx, rawURL, resource)
+
+	sw.breaker.record(callOutcome(ctx, res, err))
 
-func (sw *swapiClient) GetPeopleList() (result models.PeopleList, err error) {
-	resource := "/people/"
-	res, err := sw.client.Get(sw.baseURL + resource)
+	if res != nil {
+		span.SetAttributes(tracing.Attributes{"http.status_code": res.StatusCode})
+	}
 
 	if err != nil {
-		return result, err
+		return transportError(err)
 	}
 
 	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+
 		if res.StatusCode == http.StatusNotFound {
-			return result, errors.NewNotFound("people", "")
-		} else {
-			return result, errors.NewInternal()
+			return errors.NewNotFound(resource, id)
 		}
+
+		return statusError(res, sw.now())
 	}
 
-	err = getBody(res, &result)
+	return getBody(res, v)
+}
 
-	if err != nil {
-		return result, err
+// transportError maps an error getting a response from the upstream: timeouts
+// are served as 504 and whatever kept us from reaching it as 502
+func transportError(err error) *errors.Error {
+	var netErr net.Error
+
+	switch {
+	case stderrors.Is(err, context.DeadlineExceeded) || (stderrors.As(err, &netErr) && netErr.Timeout()):
+		return errors.NewGatewayTimeout().WithCause(err)
+	case stderrors.Is(err, context.Canceled):
+		// the client went away, nobody will see this error
+		return errors.NewInternal().WithCause(err)
+	default:
+		return errors.NewBadGateway().WithCode(errors.CodeUpstreamUnreachable).WithCause(err)
+	}
+}
+
+// statusError maps an unexpected upstream status: being rate limited is served
+// as 503, passing on the upstream Retry-After, anything else as 502
+func statusError(res *http.Response, now time.Time) *errors.Error {
+	cause := fmt.Errorf("upstream responded with status %d", res.StatusCode)
+
+	if res.StatusCode == http.StatusTooManyRequests {
+		return errors.NewUpstreamRateLimited(retryAfter(res, now)).WithCause(cause)
 	}
 
-	return result, err
+	return errors.NewBadGateway().WithDetail("upstream_status", res.StatusCode).WithCause(cause)
 }
 
+// getBody decodes the body of res into v. A body that can't be read fails like
+// the request itself, one that can't be decoded violates the upstream contract.
 func getBody(res *http.Response, v interface{}) error {
 	defer res.Body.Close()
 	body, err := ioutil.ReadAll(res.Body)
 
 	if err != nil {
-		return err
+		return transportError(fmt.Errorf("reading body: %w", err))
 	}
 
 	err = json.Unmarshal(body, v)
 
 	if err != nil {
-		return err
+		return errors.NewUpstreamContractViolation().WithCause(fmt.Errorf("decoding body: %w", err))
 	}
 
 	return nil
@@ -0,0 +1,391 @@
+package swapi
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"swapi/errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newPagedServer serves total people split in pages of pageSize, the same way
+// SWAPI does, and counts how many requests it received
+func newPagedServer(total int, pageSize int, calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+
+		if err != nil || page < 1 || (page-1)*pageSize >= total && total > 0 {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		next := "null"
+
+		if page*pageSize < total {
+			next = fmt.Sprintf(`"http://%s/people/?page=%d"`, r.Host, page+1)
+		}
+
+		results := ""
+
+		for i := (page-1)*pageSize + 1; i <= page*pageSize && i <= total; i++ {
+			if results != "" {
+				results += ","
+			}
+
+			results += fmt.Sprintf(`{"name":"person %d"}`, i)
+		}
+
+		fmt.Fprintf(rw, `{"count":%d,"next":%s,"previous":null,"results":[%s]}`, total, next, results)
+	}))
+}
+
+// newTestClient builds a bare client against server, without retries, breaker
+// or cache
+func newTestClient(server *httptest.Server) *swapiClient {
+	return &swapiClient{client: server.Client(), baseURL: server.URL, now: time.Now}
+}
+
+func TestGetAllPeople(t *testing.T) {
+
+	type TestCase struct {
+		Name              string
+		Total             int
+		PageSize          int
+		ExpectedCallCount int32
+	}
+
+	testCases := []TestCase{
+		{
+			Name:              "Single page",
+			Total:             7,
+			PageSize:          10,
+			ExpectedCallCount: 1,
+		},
+		{
+			Name:              "Multiple pages",
+			Total:             82,
+			PageSize:          10,
+			ExpectedCallCount: 9,
+		},
+		{
+			Name:              "Empty",
+			Total:             0,
+			PageSize:          10,
+			ExpectedCallCount: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var calls int32
+
+			server := newPagedServer(tc.Total, tc.PageSize, &calls)
+			defer server.Close()
+
+			client := newTestClient(server)
+
+			result, err := client.GetAllPeople(context.Background())
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.Total, result.Count)
+			assert.Len(t, result.Results, tc.Total)
+			assert.Equal(t, tc.ExpectedCallCount, atomic.LoadInt32(&calls))
+
+			for i, person := range result.Results {
+				assert.Equal(t, fmt.Sprintf("person %d", i+1), person.Name)
+			}
+		})
+	}
+
+	t.Run("Page error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("page") == "2" {
+				rw.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			fmt.Fprintf(rw, `{"count":12,"next":"http://%s/people/?page=2","results":[{},{},{},{},{},{},{},{},{},{}]}`, r.Host)
+		}))
+		defer server.Close()
+
+		client := newTestClient(server)
+
+		_, err := client.GetAllPeople(context.Background())
+
+		assert.ErrorIs(t, err, errors.NewBadGateway())
+	})
+
+	t.Run("Later page error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			switch r.URL.Query().Get("page") {
+			case "1":
+				fmt.Fprintf(rw, `{"count":30,"next":"http://%s/people/?page=2","results":[{},{},{},{},{},{},{},{},{},{}]}`, r.Host)
+			case "3":
+				rw.WriteHeader(http.StatusBadGateway)
+			default:
+				// held until the failure of page 3 cancels it
+				<-r.Context().Done()
+			}
+		}))
+		defer server.Close()
+
+		client := newTestClient(server)
+
+		_, err := client.GetAllPeople(context.Background())
+
+		assert.ErrorIs(t, err, errors.NewBadGateway())
+		assert.Equal(t, "upstream responded with status 502", errors.Cause(err), "not the cancellation of page 2")
+	})
+
+	t.Run("No page started after a failure", func(t *testing.T) {
+		var received int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&received, 1)
+
+			switch r.URL.Query().Get("page") {
+			case "1":
+				fmt.Fprintf(rw, `{"count":200,"next":"http://%s/planets/?page=2","results":[{},{},{},{},{},{},{},{},{},{}]}`, r.Host)
+			case "2":
+				rw.WriteHeader(http.StatusBadGateway)
+			default:
+				<-r.Context().Done()
+			}
+		}))
+		defer server.Close()
+
+		client := newTestClient(server)
+		attempts := upstreamDuration.Count("planets")
+
+		_, err := client.GetAllPlanets(context.Background())
+
+		assert.ErrorIs(t, err, errors.NewBadGateway())
+		// the first page, then at most the ones started with page 2
+		assert.LessOrEqual(t, atomic.LoadInt32(&received), int32(1+maxConcurrentPages))
+		assert.LessOrEqual(t, upstreamDuration.Count("planets")-attempts, uint64(1+maxConcurrentPages))
+	})
+
+	t.Run("Caller canceling", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("page") == "1" {
+				cancel()
+				fmt.Fprintf(rw, `{"count":30,"next":"http://%s/people/?page=2","results":[{},{},{},{},{},{},{},{},{},{}]}`, r.Host)
+				return
+			}
+
+			fmt.Fprint(rw, `{"count":30,"results":[{},{},{},{},{},{},{},{},{},{}]}`)
+		}))
+		defer server.Close()
+
+		client := newTestClient(server)
+
+		result, err := client.GetAllPeople(ctx)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, result.Results, "no partial collection")
+	})
+}
+
+func TestSearchStarships(t *testing.T) {
+	var searches []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		searches = append(searches, r.URL.Query().Get("search"))
+
+		assert.Equal(t, "/starships/", r.URL.Path)
+		fmt.Fprint(rw, `{"count":2,"next":null,"results":[{"name":"Death Star"},{"name":"Death Star II"}]}`)
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	result, err := client.SearchStarships(context.Background(), "death star")
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, result.Count)
+	assert.Equal(t, "Death Star II", result.Results[1].Name)
+	assert.Equal(t, []string{"death star"}, searches)
+}
+
+func TestContext(t *testing.T) {
+	t.Run("Deadline exceeded", func(t *testing.T) {
+		release := make(chan struct{})
+
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+		defer close(release)
+
+		client := newTestClient(server)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := client.GetStarship(ctx, 9)
+
+		assert.ErrorIs(t, err, errors.NewGatewayTimeout())
+		assert.ErrorIs(t, err, context.DeadlineExceeded, "the cause is kept")
+		assert.Equal(t, http.StatusGatewayTimeout, errors.Status(err))
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		canceled := make(chan struct{})
+
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+			close(canceled)
+		}))
+		defer server.Close()
+
+		client := newTestClient(server)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			cancel()
+		}()
+
+		_, err := client.GetPeople(ctx, 1)
+
+		assert.ErrorIs(t, err, context.Canceled)
+
+		select {
+		case <-canceled:
+		case <-time.After(time.Second):
+			t.Fatal("upstream request was not canceled")
+		}
+	})
+}
+
+func TestUpstreamErrors(t *testing.T) {
+
+	type TestCase struct {
+		Name               string
+		Status             int
+		RetryAfter         string
+		Body               string
+		ExpectedError      *errors.Error
+		ExpectedStatusCode int
+		ExpectedRetryAfter time.Duration
+		ExpectedCause      string
+	}
+
+	testCases := []TestCase{
+		{
+			Name:               "Server error",
+			Status:             http.StatusInternalServerError,
+			ExpectedError:      errors.NewBadGateway(),
+			ExpectedStatusCode: http.StatusBadGateway,
+			ExpectedCause:      "upstream responded with status 500",
+		},
+		{
+			Name:               "Unexpected status",
+			Status:             http.StatusTeapot,
+			ExpectedError:      errors.NewBadGateway(),
+			ExpectedStatusCode: http.StatusBadGateway,
+			ExpectedCause:      "upstream responded with status 418",
+		},
+		{
+			Name:               "Rate limited",
+			Status:             http.StatusTooManyRequests,
+			RetryAfter:         "30",
+			ExpectedError:      errors.NewUpstreamRateLimited(0),
+			ExpectedStatusCode: http.StatusServiceUnavailable,
+			ExpectedRetryAfter: 30 * time.Second,
+			ExpectedCause:      "upstream responded with status 429",
+		},
+		{
+			Name:               "Rate limited without Retry-After",
+			Status:             http.StatusTooManyRequests,
+			ExpectedError:      errors.NewUpstreamRateLimited(0),
+			ExpectedStatusCode: http.StatusServiceUnavailable,
+			ExpectedCause:      "upstream responded with status 429",
+		},
+		{
+			Name:               "Malformed body",
+			Status:             http.StatusOK,
+			Body:               `{"name":`,
+			ExpectedError:      errors.NewUpstreamContractViolation(),
+			ExpectedStatusCode: http.StatusBadGateway,
+			ExpectedCause:      "decoding body: unexpected end of JSON input",
+		},
+		{
+			Name:               "Unexpected body",
+			Status:             http.StatusOK,
+			Body:               `{"name":["Luke"]}`,
+			ExpectedError:      errors.NewUpstreamContractViolation(),
+			ExpectedStatusCode: http.StatusBadGateway,
+			ExpectedCause:      "decoding body: json: cannot unmarshal array",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				if tc.RetryAfter != "" {
+					rw.Header().Set("Retry-After", tc.RetryAfter)
+				}
+
+				rw.WriteHeader(tc.Status)
+				fmt.Fprint(rw, tc.Body)
+			}))
+			defer server.Close()
+
+			client := newTestClient(server)
+
+			_, err := client.GetPeople(context.Background(), 1)
+
+			var e *errors.Error
+			assert.True(t, stderrors.As(err, &e))
+			assert.ErrorIs(t, err, tc.ExpectedError)
+			assert.Equal(t, tc.ExpectedStatusCode, errors.Status(err))
+			assert.Equal(t, tc.ExpectedRetryAfter, e.RetryAfter)
+			assert.Contains(t, errors.Cause(err), tc.ExpectedCause)
+		})
+	}
+
+	t.Run("Timeout", func(t *testing.T) {
+		release := make(chan struct{})
+
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+		defer close(release)
+
+		client := newTestClient(server)
+		client.client.Timeout = 20 * time.Millisecond
+
+		_, err := client.GetPeople(context.Background(), 1)
+
+		assert.ErrorIs(t, err, errors.NewGatewayTimeout())
+		assert.Equal(t, http.StatusGatewayTimeout, errors.Status(err))
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		client := newTestClient(server)
+
+		_, err := client.GetPeople(context.Background(), 1)
+
+		assert.ErrorIs(t, err, errors.NewBadGateway().WithCode(errors.CodeUpstreamUnreachable))
+		assert.Equal(t, http.StatusBadGateway, errors.Status(err))
+		assert.NotEmpty(t, errors.Cause(err))
+	})
+}
@@ -0,0 +1,370 @@
+package config
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"swapi/clients/swapi"
+	"swapi/tracing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Duration is a time.Duration written as "10s" or "1m30s" in config files
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	value, err := time.ParseDuration(string(text))
+
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(value)
+
+	return nil
+}
+
+type Config struct {
+	Server   ServerConfig   `json:"server" yaml:"server"`
+	Upstream UpstreamConfig `json:"upstream" yaml:"upstream"`
+	Cache    CacheConfig    `json:"cache" yaml:"cache"`
+	Log      LogConfig      `json:"log" yaml:"log"`
+	Tracing  TracingConfig  `json:"tracing" yaml:"tracing"`
+}
+
+type ServerConfig struct {
+	Addr string `json:"addr" yaml:"addr"`
+	// ShutdownTimeout bounds how long in-flight requests are waited for on
+	// shutdown
+	ShutdownTimeout Duration `json:"shutdown_timeout" yaml:"shutdown_timeout"`
+	// ProblemDetails serves every error as RFC 7807 problem details, not
+	// only to clients accepting application/problem+json
+	ProblemDetails bool `json:"problem_details" yaml:"problem_details"`
+}
+
+type UpstreamConfig struct {
+	BaseURL                 string   `json:"base_url" yaml:"base_url"`
+	ConnectTimeout          Duration `json:"connect_timeout" yaml:"connect_timeout"`
+	Timeout                 Duration `json:"timeout" yaml:"timeout"`
+	OverallTimeout          Duration `json:"overall_timeout" yaml:"overall_timeout"`
+	RetryMaxAttempts        int      `json:"retry_max_attempts" yaml:"retry_max_attempts"`
+	RetryInitialBackoff     Duration `json:"retry_initial_backoff" yaml:"retry_initial_backoff"`
+	RetryMaxBackoff         Duration `json:"retry_max_backoff" yaml:"retry_max_backoff"`
+	BreakerFailureThreshold int      `json:"breaker_failure_threshold" yaml:"breaker_failure_threshold"`
+	BreakerCoolDown         Duration `json:"breaker_cool_down" yaml:"breaker_cool_down"`
+	PingTTL                 Duration `json:"ping_ttl" yaml:"ping_ttl"`
+}
+
+type CacheConfig struct {
+	Enabled       bool     `json:"enabled" yaml:"enabled"`
+	DefaultTTL    Duration `json:"default_ttl" yaml:"default_ttl"`
+	NotFoundTTL   Duration `json:"not_found_ttl" yaml:"not_found_ttl"`
+	MaxEntries    int      `json:"max_entries" yaml:"max_entries"`
+	SweepInterval Duration `json:"sweep_interval" yaml:"sweep_interval"`
+	// TTLs overrides DefaultTTL per resource and can only be set from a file
+	TTLs map[string]Duration `json:"ttls" yaml:"ttls"`
+}
+
+type LogConfig struct {
+	Level string `json:"level" yaml:"level"`
+}
+
+type TracingConfig struct {
+	// Exporter is one of none, stdout or otlp. Tracing is disabled with none.
+	Exporter     string `json:"exporter" yaml:"exporter"`
+	OTLPEndpoint string `json:"otlp_endpoint" yaml:"otlp_endpoint"`
+	ServiceName  string `json:"service_name" yaml:"service_name"`
+}
+
+func Default() Config {
+	client := swapi.DefaultClientOptions()
+	cache := swapi.DefaultCacheOptions()
+	otlp := tracing.DefaultOTLPOptions()
+
+	return Config{
+		Server: ServerConfig{
+			Addr:            ":3000",
+			ShutdownTimeout: Duration(15 * time.Second),
+		},
+		Upstream: UpstreamConfig{
+			BaseURL:                 client.BaseURL,
+			ConnectTimeout:          Duration(client.ConnectTimeout),
+			Timeout:                 Duration(client.Timeout),
+			OverallTimeout:          Duration(client.OverallTimeout),
+			RetryMaxAttempts:        client.Retry.MaxAttempts,
+			RetryInitialBackoff:     Duration(client.Retry.InitialBackoff),
+			RetryMaxBackoff:         Duration(client.Retry.MaxBackoff),
+			BreakerFailureThreshold: client.Breaker.FailureThreshold,
+			BreakerCoolDown:         Duration(client.Breaker.CoolDown),
+			PingTTL:                 Duration(client.PingTTL),
+		},
+		Cache: CacheConfig{
+			Enabled:       true,
+			DefaultTTL:    Duration(cache.DefaultTTL),
+			NotFoundTTL:   Duration(cache.NotFoundTTL),
+			MaxEntries:    cache.MaxEntries,
+			SweepInterval: Duration(cache.SweepInterval),
+		},
+		Log: LogConfig{
+			Level: "info",
+		},
+		Tracing: TracingConfig{
+			Exporter:     "none",
+			OTLPEndpoint: otlp.Endpoint,
+			ServiceName:  otlp.ServiceName,
+		},
+	}
+}
+
+func (c Config) ClientOptions() swapi.ClientOptions {
+	return swapi.ClientOptions{
+		BaseURL:        c.Upstream.BaseURL,
+		ConnectTimeout: time.Duration(c.Upstream.ConnectTimeout),
+		Timeout:        time.Duration(c.Upstream.Timeout),
+		OverallTimeout: time.Duration(c.Upstream.OverallTimeout),
+		Retry: swapi.RetryPolicy{
+			MaxAttempts:    c.Upstream.RetryMaxAttempts,
+			InitialBackoff: time.Duration(c.Upstream.RetryInitialBackoff),
+			MaxBackoff:     time.Duration(c.Upstream.RetryMaxBackoff),
+		},
+		Breaker: swapi.BreakerOptions{
+			FailureThreshold: c.Upstream.BreakerFailureThreshold,
+			CoolDown:         time.Duration(c.Upstream.BreakerCoolDown),
+		},
+		PingTTL: time.Duration(c.Upstream.PingTTL),
+	}
+}
+
+func (c Config) CacheOptions() swapi.CacheOptions {
+	ttls := map[string]time.Duration{}
+
+	for resource, ttl := range c.Cache.TTLs {
+		ttls[resource] = time.Duration(ttl)
+	}
+
+	return swapi.CacheOptions{
+		DefaultTTL:    time.Duration(c.Cache.DefaultTTL),
+		TTLs:          ttls,
+		NotFoundTTL:   time.Duration(c.Cache.NotFoundTTL),
+		MaxEntries:    c.Cache.MaxEntries,
+		SweepInterval: time.Duration(c.Cache.SweepInterval),
+	}
+}
+
+func (c Config) OTLPOptions() tracing.OTLPOptions {
+	options := tracing.DefaultOTLPOptions()
+	options.Endpoint = c.Tracing.OTLPEndpoint
+	options.ServiceName = c.Tracing.ServiceName
+
+	return options
+}
+
+// setting is a value that can be given both as an environment variable and
+// as a command-line flag
+type setting struct {
+	env   string
+	flag  string
+	usage string
+	set   func(c *Config, value string) error
+}
+
+func stringSetting(env, flag, usage string, field func(c *Config) *string) setting {
+	return setting{env, flag, usage, func(c *Config, value string) error {
+		*field(c) = value
+		return nil
+	}}
+}
+
+func durationSetting(env, flag, usage string, field func(c *Config) *Duration) setting {
+	return setting{env, flag, usage, func(c *Config, value string) error {
+		return field(c).UnmarshalText([]byte(value))
+	}}
+}
+
+func intSetting(env, flag, usage string, field func(c *Config) *int) setting {
+	return setting{env, flag, usage, func(c *Config, value string) (err error) {
+		*field(c), err = strconv.Atoi(value)
+		return err
+	}}
+}
+
+func boolSetting(env, flag, usage string, field func(c *Config) *bool) setting {
+	return setting{env, flag, usage, func(c *Config, value string) (err error) {
+		*field(c), err = strconv.ParseBool(value)
+		return err
+	}}
+}
+
+var settings = []setting{
+	stringSetting("SWAPI_ADDR", "addr", "address the server listens on", func(c *Config) *string { return &c.Server.Addr }),
+	durationSetting("SWAPI_SHUTDOWN_TIMEOUT", "shutdown-timeout", "how long in-flight requests are waited for on shutdown", func(c *Config) *Duration { return &c.Server.ShutdownTimeout }),
+	boolSetting("SWAPI_PROBLEM_DETAILS", "problem-details", "serve every error as application/problem+json", func(c *Config) *bool { return &c.Server.ProblemDetails }),
+	stringSetting("SWAPI_UPSTREAM_URL", "upstream-url", "SWAPI base URL", func(c *Config) *string { return &c.Upstream.BaseURL }),
+	durationSetting("SWAPI_UPSTREAM_CONNECT_TIMEOUT", "upstream-connect-timeout", "timeout to connect to the upstream", func(c *Config) *Duration { return &c.Upstream.ConnectTimeout }),
+	durationSetting("SWAPI_UPSTREAM_TIMEOUT", "upstream-timeout", "timeout of a single upstream attempt", func(c *Config) *Duration { return &c.Upstream.Timeout }),
+	durationSetting("SWAPI_UPSTREAM_OVERALL_TIMEOUT", "upstream-overall-timeout", "timeout of a whole upstream call, retries included", func(c *Config) *Duration { return &c.Upstream.OverallTimeout }),
+	intSetting("SWAPI_RETRY_MAX_ATTEMPTS", "retry-max-attempts", "upstream attempts per call, 1 disables retries", func(c *Config) *int { return &c.Upstream.RetryMaxAttempts }),
+	durationSetting("SWAPI_RETRY_INITIAL_BACKOFF", "retry-initial-backoff", "wait before the first retry", func(c *Config) *Duration { return &c.Upstream.RetryInitialBackoff }),
+	durationSetting("SWAPI_RETRY_MAX_BACKOFF", "retry-max-backoff", "longest wait between retries", func(c *Config) *Duration { return &c.Upstream.RetryMaxBackoff }),
+	intSetting("SWAPI_BREAKER_FAILURE_THRESHOLD", "breaker-failure-threshold", "consecutive failures that open the circuit breaker, 0 disables it", func(c *Config) *int { return &c.Upstream.BreakerFailureThreshold }),
+	durationSetting("SWAPI_BREAKER_COOL_DOWN", "breaker-cool-down", "how long the circuit breaker stays open", func(c *Config) *Duration { return &c.Upstream.BreakerCoolDown }),
+	durationSetting("SWAPI_UPSTREAM_PING_TTL", "upstream-ping-ttl", "how long a readiness check of the upstream is reused", func(c *Config) *Duration { return &c.Upstream.PingTTL }),
+	boolSetting("SWAPI_CACHE_ENABLED", "cache-enabled", "cache upstream responses", func(c *Config) *bool { return &c.Cache.Enabled }),
+	durationSetting("SWAPI_CACHE_TTL", "cache-ttl", "how long upstream responses are cached", func(c *Config) *Duration { return &c.Cache.DefaultTTL }),
+	durationSetting("SWAPI_CACHE_NOT_FOUND_TTL", "cache-not-found-ttl", "how long upstream 404s are cached", func(c *Config) *Duration { return &c.Cache.NotFoundTTL }),
+	intSetting("SWAPI_CACHE_MAX_ENTRIES", "cache-max-entries", "maximum number of cached responses", func(c *Config) *int { return &c.Cache.MaxEntries }),
+	durationSetting("SWAPI_CACHE_SWEEP_INTERVAL", "cache-sweep-interval", "how often expired cache entries are purged, 0 disables it", func(c *Config) *Duration { return &c.Cache.SweepInterval }),
+	stringSetting("SWAPI_LOG_LEVEL", "log-level", "one of debug, info, warn or error", func(c *Config) *string { return &c.Log.Level }),
+	stringSetting("SWAPI_TRACING_EXPORTER", "tracing-exporter", "one of none, stdout or otlp", func(c *Config) *string { return &c.Tracing.Exporter }),
+	stringSetting("SWAPI_TRACING_OTLP_ENDPOINT", "tracing-otlp-endpoint", "OTLP/HTTP traces URL of the collector", func(c *Config) *string { return &c.Tracing.OTLPEndpoint }),
+	stringSetting("SWAPI_TRACING_SERVICE_NAME", "tracing-service-name", "service name reported in traces", func(c *Config) *string { return &c.Tracing.ServiceName }),
+}
+
+// Load builds the configuration from, in increasing order of precedence, the
+// defaults, an optional YAML or JSON file given by -config or SWAPI_CONFIG,
+// environment variables and command-line flags
+func Load(args []string, getenv func(string) string) (Config, error) {
+	cfg := Default()
+
+	flags := flag.NewFlagSet("swapi", flag.ContinueOnError)
+	path := flags.String("config", getenv("SWAPI_CONFIG"), "YAML or JSON config file")
+
+	for _, s := range settings {
+		flags.String(s.flag, "", fmt.Sprintf("%s (env %s)", s.usage, s.env))
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return cfg, err
+	}
+
+	if *path != "" {
+		if err := loadFile(*path, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+
+	for _, s := range settings {
+		if value := getenv(s.env); value != "" {
+			if err := s.set(&cfg, value); err != nil {
+				return cfg, fmt.Errorf("invalid %s: %w", s.env, err)
+			}
+		}
+	}
+
+	var err error
+
+	flags.Visit(func(f *flag.Flag) {
+		for _, s := range settings {
+			if s.flag == f.Name && err == nil {
+				if setErr := s.set(&cfg, f.Value.String()); setErr != nil {
+					err = fmt.Errorf("invalid -%s: %w", s.flag, setErr)
+				}
+			}
+		}
+	})
+
+	if err != nil {
+		return cfg, err
+	}
+
+	return cfg, cfg.Validate()
+}
+
+func loadFile(path string, cfg *Config) error {
+	content, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, cfg)
+	case ".json":
+		err = json.Unmarshal(content, cfg)
+	default:
+		return fmt.Errorf("config file %s must be .yaml, .yml or .json", path)
+	}
+
+	if err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Validate reports every invalid setting at once
+func (c Config) Validate() error {
+	var problems []string
+
+	check := func(ok bool, problem string) {
+		if !ok {
+			problems = append(problems, problem)
+		}
+	}
+
+	check(c.Server.Addr != "", "server address is required")
+	check(c.Server.ShutdownTimeout > 0, "shutdown timeout must be positive")
+
+	upstream, err := url.Parse(c.Upstream.BaseURL)
+	check(err == nil && (upstream.Scheme == "http" || upstream.Scheme == "https") && upstream.Host != "", "upstream URL must be an absolute http(s) URL")
+
+	check(c.Upstream.ConnectTimeout > 0, "upstream connect timeout must be positive")
+	check(c.Upstream.Timeout > 0, "upstream timeout must be positive")
+	check(c.Upstream.OverallTimeout >= c.Upstream.Timeout, "upstream overall timeout can't be lower than the timeout")
+	check(c.Upstream.RetryMaxAttempts >= 1, "retry max attempts must be at least 1")
+	check(c.Upstream.RetryInitialBackoff >= 0, "retry initial backoff can't be negative")
+	check(c.Upstream.RetryMaxBackoff >= c.Upstream.RetryInitialBackoff, "retry max backoff can't be lower than the initial backoff")
+	check(c.Upstream.BreakerFailureThreshold >= 0, "breaker failure threshold can't be negative")
+	check(c.Upstream.BreakerFailureThreshold == 0 || c.Upstream.BreakerCoolDown > 0, "breaker cool down must be positive")
+	check(c.Upstream.PingTTL >= 0, "upstream ping TTL can't be negative")
+	check(c.Cache.DefaultTTL >= 0, "cache TTL can't be negative")
+	check(c.Cache.NotFoundTTL >= 0, "cache not found TTL can't be negative")
+	check(c.Cache.MaxEntries >= 0, "cache max entries can't be negative")
+	check(c.Cache.SweepInterval >= 0, "cache sweep interval can't be negative")
+
+	resources := make([]string, 0, len(c.Cache.TTLs))
+
+	for resource := range c.Cache.TTLs {
+		resources = append(resources, resource)
+	}
+
+	sort.Strings(resources)
+
+	for _, resource := range resources {
+		check(c.Cache.TTLs[resource] >= 0, fmt.Sprintf("cache TTL of %s can't be negative", resource))
+	}
+
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		problems = append(problems, "log level must be one of debug, info, warn or error")
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		endpoint, err := url.Parse(c.Tracing.OTLPEndpoint)
+		check(err == nil && (endpoint.Scheme == "http" || endpoint.Scheme == "https") && endpoint.Host != "", "tracing OTLP endpoint must be an absolute http(s) URL")
+	default:
+		problems = append(problems, "tracing exporter must be one of none, stdout or otlp")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid config: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"swapi/clients/swapi"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func env(values map[string]string) func(string) string {
+	return func(key string) string {
+		return values[key]
+	}
+}
+
+func TestLoad(t *testing.T) {
+	yamlFile := `
+server:
+  addr: ":4000"
+upstream:
+  base_url: "http://swapi.local/api"
+  timeout: 5s
+cache:
+  default_ttl: 1m
+  ttls:
+    films: 24h
+log:
+  level: debug
+`
+
+	jsonFile := `{"server":{"addr":":5000"},"upstream":{"retry_max_attempts":5},"cache":{"enabled":false}}`
+
+	t.Run("Defaults", func(t *testing.T) {
+		cfg, err := Load(nil, env(nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, Default(), cfg)
+		assert.Equal(t, ":3000", cfg.Server.Addr)
+		assert.Equal(t, swapi.DefaultClientOptions(), cfg.ClientOptions())
+	})
+
+	t.Run("YAML file", func(t *testing.T) {
+		path := writeFile(t, "config.yaml", yamlFile)
+
+		cfg, err := Load([]string{"-config", path}, env(nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, ":4000", cfg.Server.Addr)
+		assert.Equal(t, "http://swapi.local/api", cfg.Upstream.BaseURL)
+		assert.Equal(t, Duration(5*time.Second), cfg.Upstream.Timeout)
+		assert.Equal(t, Default().Upstream.ConnectTimeout, cfg.Upstream.ConnectTimeout)
+		assert.Equal(t, "debug", cfg.Log.Level)
+		assert.Equal(t, map[string]time.Duration{"films": 24 * time.Hour}, cfg.CacheOptions().TTLs)
+		assert.Equal(t, time.Minute, cfg.CacheOptions().DefaultTTL)
+	})
+
+	t.Run("JSON file from environment", func(t *testing.T) {
+		path := writeFile(t, "config.json", jsonFile)
+
+		cfg, err := Load(nil, env(map[string]string{"SWAPI_CONFIG": path}))
+
+		assert.Nil(t, err)
+		assert.Equal(t, ":5000", cfg.Server.Addr)
+		assert.Equal(t, 5, cfg.Upstream.RetryMaxAttempts)
+		assert.False(t, cfg.Cache.Enabled)
+	})
+
+	t.Run("Environment overrides file", func(t *testing.T) {
+		path := writeFile(t, "config.yaml", yamlFile)
+
+		cfg, err := Load([]string{"-config", path}, env(map[string]string{
+			"SWAPI_ADDR":             ":6000",
+			"SWAPI_UPSTREAM_TIMEOUT": "7s",
+			"SWAPI_CACHE_ENABLED":    "false",
+		}))
+
+		assert.Nil(t, err)
+		assert.Equal(t, ":6000", cfg.Server.Addr)
+		assert.Equal(t, Duration(7*time.Second), cfg.Upstream.Timeout)
+		assert.Equal(t, "http://swapi.local/api", cfg.Upstream.BaseURL)
+		assert.False(t, cfg.Cache.Enabled)
+	})
+
+	t.Run("Flags override environment", func(t *testing.T) {
+		cfg, err := Load([]string{"-addr", ":7000", "-retry-max-attempts", "1"}, env(map[string]string{
+			"SWAPI_ADDR":               ":6000",
+			"SWAPI_RETRY_MAX_ATTEMPTS": "4",
+			"SWAPI_LOG_LEVEL":          "warn",
+		}))
+
+		assert.Nil(t, err)
+		assert.Equal(t, ":7000", cfg.Server.Addr)
+		assert.Equal(t, 1, cfg.Upstream.RetryMaxAttempts)
+		assert.Equal(t, "warn", cfg.Log.Level)
+	})
+
+	type ErrorCase struct {
+		Name          string
+		Args          []string
+		Env           map[string]string
+		ExpectedError string
+	}
+
+	errorCases := []ErrorCase{
+		{
+			Name:          "Unknown flag",
+			Args:          []string{"-nope"},
+			ExpectedError: "flag provided but not defined: -nope",
+		},
+		{
+			Name:          "Invalid environment value",
+			Env:           map[string]string{"SWAPI_UPSTREAM_TIMEOUT": "soon"},
+			ExpectedError: `invalid SWAPI_UPSTREAM_TIMEOUT: time: invalid duration "soon"`,
+		},
+		{
+			Name:          "Invalid flag value",
+			Args:          []string{"-cache-max-entries", "many"},
+			ExpectedError: `invalid -cache-max-entries: strconv.Atoi: parsing "many": invalid syntax`,
+		},
+		{
+			Name:          "Missing file",
+			Args:          []string{"-config", "/nowhere/config.yaml"},
+			ExpectedError: "reading config file: open /nowhere/config.yaml: no such file or directory",
+		},
+		{
+			Name:          "Validation",
+			Args:          []string{"-upstream-url", "swapi.dev", "-retry-max-attempts", "0", "-log-level", "loud"},
+			ExpectedError: "invalid config: upstream URL must be an absolute http(s) URL; retry max attempts must be at least 1; log level must be one of debug, info, warn or error",
+		},
+		{
+			Name:          "Overall timeout below the attempt timeout",
+			Args:          []string{"-upstream-timeout", "10s", "-upstream-overall-timeout", "5s"},
+			ExpectedError: "invalid config: upstream overall timeout can't be lower than the timeout",
+		},
+		{
+			Name:          "Unknown tracing exporter",
+			Env:           map[string]string{"SWAPI_TRACING_EXPORTER": "jaeger"},
+			ExpectedError: "invalid config: tracing exporter must be one of none, stdout or otlp",
+		},
+		{
+			Name:          "Invalid OTLP endpoint",
+			Args:          []string{"-tracing-exporter", "otlp", "-tracing-otlp-endpoint", "collector:4318"},
+			ExpectedError: "invalid config: tracing OTLP endpoint must be an absolute http(s) URL",
+		},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := Load(tc.Args, env(tc.Env))
+
+			assert.EqualError(t, err, tc.ExpectedError)
+		})
+	}
+
+	t.Run("Unsupported file extension", func(t *testing.T) {
+		path := writeFile(t, "config.toml", "")
+
+		_, err := Load([]string{"-config", path}, env(nil))
+
+		assert.EqualError(t, err, "config file "+path+" must be .yaml, .yml or .json")
+	})
+
+	t.Run("Malformed file", func(t *testing.T) {
+		path := writeFile(t, "config.json", `{"upstream":{"timeout":"forever"}}`)
+
+		_, err := Load([]string{"-config", path}, env(nil))
+
+		assert.ErrorContains(t, err, "parsing config file")
+	})
+}
@@ -4,36 +4,192 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 )
 
 type Type string
 
 const (
-	BadRequest Type = "BAD_REQUEST"
-	Internal   Type = "INTERNAL_SERVER_ERROR"
-	NotFound   Type = "NOT_FOUND"
+	BadRequest          Type = "BAD_REQUEST"
+	Unauthorized        Type = "UNAUTHORIZED"
+	Forbidden           Type = "FORBIDDEN"
+	NotFound            Type = "NOT_FOUND"
+	NotAcceptable       Type = "NOT_ACCEPTABLE"
+	Conflict            Type = "CONFLICT"
+	TooManyRequests     Type = "TOO_MANY_REQUESTS"
+	Internal            Type = "INTERNAL_SERVER_ERROR"
+	BadGateway          Type = "BAD_GATEWAY"
+	ServiceUnavailable  Type = "SERVICE_UNAVAILABLE"
+	UpstreamUnavailable Type = "UPSTREAM_UNAVAILABLE"
+	GatewayTimeout      Type = "GATEWAY_TIMEOUT"
+	// UpstreamContractViolation is for upstream answers we can't make sense
+	// of, such as malformed bodies
+	UpstreamContractViolation Type = "UPSTREAM_CONTRACT_VIOLATION"
+)
+
+// typeInfo is how errors of a Type are served
+type typeInfo struct {
+	status int
+	title  string
+}
+
+var (
+	typesMu sync.RWMutex
+	types   = map[Type]typeInfo{}
+)
+
+func init() {
+	Register(BadRequest, http.StatusBadRequest, "")
+	Register(Unauthorized, http.StatusUnauthorized, "")
+	Register(Forbidden, http.StatusForbidden, "")
+	Register(NotFound, http.StatusNotFound, "")
+	Register(NotAcceptable, http.StatusNotAcceptable, "")
+	Register(Conflict, http.StatusConflict, "")
+	Register(TooManyRequests, http.StatusTooManyRequests, "")
+	Register(Internal, http.StatusInternalServerError, "")
+	Register(BadGateway, http.StatusBadGateway, "")
+	Register(ServiceUnavailable, http.StatusServiceUnavailable, "")
+	Register(UpstreamUnavailable, http.StatusServiceUnavailable, "Upstream Unavailable")
+	Register(GatewayTimeout, http.StatusGatewayTimeout, "")
+	Register(UpstreamContractViolation, http.StatusBadGateway, "Upstream Contract Violation")
+}
+
+// Register sets the status errors of type t are served with and their short
+// human readable title, the status text when empty. Types that aren't
+// registered are served as internal errors.
+func Register(t Type, status int, title string) {
+	if title == "" {
+		title = http.StatusText(status)
+	}
+
+	typesMu.Lock()
+	defer typesMu.Unlock()
+
+	types[t] = typeInfo{status: status, title: title}
+}
+
+func (t Type) info() typeInfo {
+	typesMu.RLock()
+	info, ok := types[t]
+	typesMu.RUnlock()
+
+	if !ok && t != Internal {
+		return Internal.info()
+	}
+
+	return info
+}
+
+// Status is the HTTP status errors of type t are served with
+func (t Type) Status() int {
+	return t.info().status
+}
+
+func (t Type) Title() string {
+	return t.info().title
+}
+
+// Stable machine readable codes, more specific than the type of an error.
+// Clients can rely on them, unlike messages.
+const (
+	CodeInvalidRequest            = "invalid_request"
+	CodeUnauthenticated           = "unauthenticated"
+	CodeForbidden                 = "forbidden"
+	CodeResourceNotFound          = "resource_not_found"
+	CodeNotAcceptable             = "not_acceptable"
+	CodeConflict                  = "conflict"
+	CodeRateLimited               = "rate_limited"
+	CodeInternal                  = "internal_error"
+	CodeUpstreamError             = "upstream_error"
+	CodeUnavailable               = "service_unavailable"
+	CodeUpstreamCircuitOpen       = "upstream_circuit_open"
+	CodeUpstreamTimeout           = "upstream_timeout"
+	CodeUpstreamUnreachable       = "upstream_unreachable"
+	CodeUpstreamRateLimited       = "upstream_rate_limited"
+	CodeUpstreamContractViolation = "upstream_contract_violation"
 )
 
 type Error struct {
 	Type    Type   `json:"type"`
+	Code    string `json:"code"`
 	Message string `json:"message"`
+	// Details are structured data about the error, served to clients
+	Details map[string]interface{} `json:"details,omitempty"`
+	// Resource and ID tell what the error is about, when it is about a
+	// resource. They are only shown in problem details.
+	Resource string `json:"-"`
+	ID       string `json:"-"`
+	// RetryAfter tells clients how long to wait before trying again, when
+	// it's known. It's served as the Retry-After header.
+	RetryAfter time.Duration `json:"-"`
+
+	// cause is what made the error happen. It is logged but never served.
+	cause error
 }
 
 func (e *Error) Error() string {
 	return e.Message
 }
 
-func (e *Error) Status() int {
-	switch e.Type {
-	case BadRequest:
-		return http.StatusBadRequest
-	case Internal:
-		return http.StatusInternalServerError
-	case NotFound:
-		return http.StatusNotFound
-	default:
-		return http.StatusInternalServerError
+// Unwrap returns the cause of e, so that errors.Is and errors.As see it
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is tells whether e is the same error as target, an *Error of the same type
+// and code, whatever their messages and causes
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+
+	return ok && e.Type == t.Type && e.Code == t.Code
+}
+
+// WithCause sets the cause of e and returns e
+func (e *Error) WithCause(cause error) *Error {
+	e.cause = cause
+	return e
+}
+
+// WithCode overrides the code of e and returns e
+func (e *Error) WithCode(code string) *Error {
+	e.Code = code
+	return e
+}
+
+// WithRetryAfter sets how long clients should wait before trying again and
+// returns e
+func (e *Error) WithRetryAfter(d time.Duration) *Error {
+	e.RetryAfter = d
+	return e
+}
+
+// WithDetail adds a detail to e and returns e
+func (e *Error) WithDetail(key string, value interface{}) *Error {
+	if e.Details == nil {
+		e.Details = map[string]interface{}{}
 	}
+
+	e.Details[key] = value
+
+	return e
+}
+
+// Cause returns the cause of err, when it is an *Error, as a string suitable
+// for logs. It is empty when there is none.
+func Cause(err error) string {
+	var e *Error
+
+	if !errors.As(err, &e) || e.cause == nil {
+		return ""
+	}
+
+	return e.cause.Error()
+}
+
+func (e *Error) Status() int {
+	return e.Type.Status()
 }
 
 func Status(err error) int {
@@ -53,6 +209,7 @@ func Status(err error) int {
 func NewBadRequest(reason string) *Error {
 	return &Error{
 		Type:    BadRequest,
+		Code:    CodeInvalidRequest,
 		Message: fmt.Sprintf("Bad request. Reason: %v", reason),
 	}
 }
@@ -61,6 +218,7 @@ func NewBadRequest(reason string) *Error {
 func NewInternal() *Error {
 	return &Error{
 		Type:    Internal,
+		Code:    CodeInternal,
 		Message: "Internal server error.",
 	}
 }
@@ -76,7 +234,116 @@ func NewNotFound(name string, value string) *Error {
 	}
 
 	return &Error{
-		Type:    NotFound,
-		Message: message,
+		Type:     NotFound,
+		Code:     CodeResourceNotFound,
+		Message:  message,
+		Resource: name,
+		ID:       value,
+	}
+}
+
+// NewNotAcceptable for 406 errors, when the response can't be served in any
+// media type the client accepts. The supported ones are given as details.
+func NewNotAcceptable(supported ...string) *Error {
+	return (&Error{
+		Type:    NotAcceptable,
+		Code:    CodeNotAcceptable,
+		Message: fmt.Sprintf("Not acceptable. Supported media types: %v", strings.Join(supported, ", ")),
+	}).WithDetail("supported", supported)
+}
+
+// NewGatewayTimeout for 504 errors, when the upstream didn't answer in time
+func NewGatewayTimeout() *Error {
+	return &Error{
+		Type:    GatewayTimeout,
+		Code:    CodeUpstreamTimeout,
+		Message: "Gateway timeout. The upstream server didn't respond in time.",
+	}
+}
+
+// NewUpstreamUnavailable for 503 errors, when calls to the upstream are being
+// refused because it keeps failing
+func NewUpstreamUnavailable() *Error {
+	return &Error{
+		Type:    UpstreamUnavailable,
+		Code:    CodeUpstreamCircuitOpen,
+		Message: "Upstream unavailable. Try again later.",
+	}
+}
+
+// NewUnauthorized for 401 errors, when the client isn't authenticated
+func NewUnauthorized(reason string) *Error {
+	return &Error{
+		Type:    Unauthorized,
+		Code:    CodeUnauthenticated,
+		Message: fmt.Sprintf("Unauthorized. Reason: %v", reason),
+	}
+}
+
+// NewForbidden for 403 errors, when the client isn't allowed to do something
+func NewForbidden(reason string) *Error {
+	return &Error{
+		Type:    Forbidden,
+		Code:    CodeForbidden,
+		Message: fmt.Sprintf("Forbidden. Reason: %v", reason),
+	}
+}
+
+// NewConflict for 409 errors, when a request conflicts with the state of a
+// resource
+func NewConflict(reason string) *Error {
+	return &Error{
+		Type:    Conflict,
+		Code:    CodeConflict,
+		Message: fmt.Sprintf("Conflict. Reason: %v", reason),
+	}
+}
+
+// NewTooManyRequests for 429 errors, when the client is being rate limited
+func NewTooManyRequests() *Error {
+	return &Error{
+		Type:    TooManyRequests,
+		Code:    CodeRateLimited,
+		Message: "Too many requests. Try again later.",
+	}
+}
+
+// NewBadGateway for 502 errors, when the upstream answered with an error
+func NewBadGateway() *Error {
+	return &Error{
+		Type:    BadGateway,
+		Code:    CodeUpstreamError,
+		Message: "Bad gateway. The upstream server failed to handle the request.",
+	}
+}
+
+// NewServiceUnavailable for 503 errors, when the service can't handle requests
+// for now
+func NewServiceUnavailable() *Error {
+	return &Error{
+		Type:    ServiceUnavailable,
+		Code:    CodeUnavailable,
+		Message: "Service unavailable. Try again later.",
+	}
+}
+
+// NewUpstreamRateLimited for 503 errors, when the upstream is rate limiting
+// us. retryAfter is what the upstream asked to wait, 0 when it didn't say.
+func NewUpstreamRateLimited(retryAfter time.Duration) *Error {
+	return &Error{
+		Type:       ServiceUnavailable,
+		Code:       CodeUpstreamRateLimited,
+		Message:    "Service unavailable. The upstream server is rate limiting requests, try again later.",
+		RetryAfter: retryAfter,
+	}
+}
+
+// NewUpstreamContractViolation for 502 errors, when the upstream answered
+// something we can't make sense of
+func NewUpstreamContractViolation() *Error {
+	return &Error{
+		Type:    UpstreamContractViolation,
+		Code:    CodeUpstreamContractViolation,
+		Message: "Bad gateway. The upstream server sent an invalid response.",
 	}
 }
@@ -0,0 +1,82 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatus(t *testing.T) {
+
+	type TestCase struct {
+		Name           string
+		Err            error
+		ExpectedStatus int
+		ExpectedTitle  string
+	}
+
+	testCases := []TestCase{
+		{Name: "Bad request", Err: NewBadRequest("invalid id"), ExpectedStatus: http.StatusBadRequest, ExpectedTitle: "Bad Request"},
+		{Name: "Unauthorized", Err: NewUnauthorized("missing token"), ExpectedStatus: http.StatusUnauthorized, ExpectedTitle: "Unauthorized"},
+		{Name: "Forbidden", Err: NewForbidden("read only"), ExpectedStatus: http.StatusForbidden, ExpectedTitle: "Forbidden"},
+		{Name: "Not found", Err: NewNotFound("people", "1"), ExpectedStatus: http.StatusNotFound, ExpectedTitle: "Not Found"},
+		{Name: "Conflict", Err: NewConflict("already exists"), ExpectedStatus: http.StatusConflict, ExpectedTitle: "Conflict"},
+		{Name: "Too many requests", Err: NewTooManyRequests(), ExpectedStatus: http.StatusTooManyRequests, ExpectedTitle: "Too Many Requests"},
+		{Name: "Internal", Err: NewInternal(), ExpectedStatus: http.StatusInternalServerError, ExpectedTitle: "Internal Server Error"},
+		{Name: "Bad gateway", Err: NewBadGateway(), ExpectedStatus: http.StatusBadGateway, ExpectedTitle: "Bad Gateway"},
+		{Name: "Service unavailable", Err: NewServiceUnavailable(), ExpectedStatus: http.StatusServiceUnavailable, ExpectedTitle: "Service Unavailable"},
+		{Name: "Upstream unavailable", Err: NewUpstreamUnavailable(), ExpectedStatus: http.StatusServiceUnavailable, ExpectedTitle: "Upstream Unavailable"},
+		{Name: "Gateway timeout", Err: NewGatewayTimeout(), ExpectedStatus: http.StatusGatewayTimeout, ExpectedTitle: "Gateway Timeout"},
+		{Name: "Upstream rate limited", Err: NewUpstreamRateLimited(time.Minute), ExpectedStatus: http.StatusServiceUnavailable, ExpectedTitle: "Service Unavailable"},
+		{Name: "Upstream contract violation", Err: NewUpstreamContractViolation(), ExpectedStatus: http.StatusBadGateway, ExpectedTitle: "Upstream Contract Violation"},
+		{Name: "Wrapped", Err: fmt.Errorf("fetching: %w", NewNotFound("people", "1")), ExpectedStatus: http.StatusNotFound, ExpectedTitle: "Not Found"},
+		{Name: "Unregistered type", Err: &Error{Type: "TEAPOT"}, ExpectedStatus: http.StatusInternalServerError, ExpectedTitle: "Internal Server Error"},
+		{Name: "Untyped", Err: fmt.Errorf("boom"), ExpectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.ExpectedStatus, Status(tc.Err))
+
+			if e, ok := tc.Err.(*Error); ok {
+				assert.Equal(t, tc.ExpectedTitle, e.Type.Title())
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	teapot := Type("TEAPOT_TEST")
+
+	Register(teapot, http.StatusTeapot, "")
+	assert.Equal(t, http.StatusTeapot, Status(&Error{Type: teapot}))
+	assert.Equal(t, "I'm a teapot", teapot.Title())
+
+	Register(teapot, http.StatusTeapot, "Short and stout")
+	assert.Equal(t, "Short and stout", teapot.Title())
+}
+
+func TestCause(t *testing.T) {
+	cause := fmt.Errorf("decoding body: %w", io.ErrUnexpectedEOF)
+	err := NewInternal().WithCause(cause)
+
+	assert.Equal(t, cause, errors.Unwrap(err))
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+	assert.True(t, errors.Is(fmt.Errorf("fetching: %w", err), NewInternal()))
+	assert.False(t, errors.Is(err, NewGatewayTimeout()))
+	assert.False(t, errors.Is(err, NewInternal().WithCode(CodeUpstreamError)))
+
+	assert.Equal(t, "Internal server error.", err.Error(), "the cause isn't part of the message")
+	assert.Equal(t, "decoding body: unexpected EOF", Cause(fmt.Errorf("fetching: %w", err)))
+	assert.Equal(t, "", Cause(NewInternal()))
+	assert.Equal(t, "", Cause(cause))
+
+	body, _ := json.Marshal(NewNotFound("people", "1").WithCause(cause).WithDetail("page", 2))
+	assert.JSONEq(t, `{"type":"NOT_FOUND","code":"resource_not_found","message":"resource: people with id: 1 not found","details":{"page":2}}`, string(body))
+}
@@ -1,31 +1,68 @@
 package httphelpers
 
 import (
+	stderrors "errors"
+	"math"
 	"net/http"
+	"strconv"
 	"swapi/errors"
+	"swapi/logging"
 	"swapi/utils"
 )
 
-func BadRequest(rw http.ResponseWriter, err error) {
-	rw.WriteHeader(http.StatusBadRequest)
-	rw.Header().Add("Content-Type", "application/json")
-	rw.Write(utils.ToJSON(err))
+// errorBody adds the request ID, when the response carries one, to err
+func errorBody(rw http.ResponseWriter, err error) interface{} {
+	id := rw.Header().Get(logging.RequestIDHeader)
+
+	var e *errors.Error
+
+	if id == "" || !stderrors.As(err, &e) {
+		return err
+	}
+
+	return struct {
+		*errors.Error
+		RequestID string `json:"request_id"`
+	}{e, id}
 }
 
-func InternalServerError(rw http.ResponseWriter) {
-	rw.WriteHeader(http.StatusInternalServerError)
-	rw.Header().Add("Content-Type", "application/json")
-	rw.Write(utils.ToJSON(errors.NewInternal()))
+// Error serves err with the status of its type. Errors that aren't typed are
+// served as internal errors, without their details. err is recorded for
+// the request log, causes included. Errors telling when to try again are
+// served with a Retry-After header.
+func Error(rw http.ResponseWriter, r *http.Request, err error) {
+	logging.SetServedError(r.Context(), err)
+
+	var e *errors.Error
+
+	if !stderrors.As(err, &e) {
+		e = errors.NewInternal()
+		err = e
+	}
+
+	if e.RetryAfter > 0 {
+		rw.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(e.RetryAfter.Seconds()))))
+	}
+
+	status := errors.Status(err)
+
+	if wantsProblem(r) {
+		writeProblem(rw, r, status, err)
+		return
+	}
+
+	JSON(rw, status, errorBody(rw, err))
 }
 
-func NotFound(rw http.ResponseWriter, err error) {
-	rw.WriteHeader(http.StatusNotFound)
-	rw.Header().Add("Content-Type", "application/json")
-	rw.Write(utils.ToJSON(err))
+// OK serves data in the format negotiated with the client, see Render
+func OK(rw http.ResponseWriter, r *http.Request, data interface{}) {
+	Render(rw, r, http.StatusOK, data)
 }
 
-func OK(rw http.ResponseWriter, data interface{}) {
-	rw.WriteHeader(http.StatusOK)
-	rw.Header().Add("Content-Type", "application/json")
+// JSON writes data as JSON with the given status, whatever the client accepts
+func JSON(rw http.ResponseWriter, status int, data interface{}) {
+	// headers set once WriteHeader is called aren't sent
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
 	rw.Write(utils.ToJSON(data))
 }
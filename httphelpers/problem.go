@@ -0,0 +1,120 @@
+package httphelpers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"swapi/errors"
+	"swapi/logging"
+	"swapi/utils"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details
+const ProblemContentType = "application/problem+json"
+
+// ProblemDetails serves every error as problem details, whatever the client
+// accepts. Otherwise only clients asking for them get problem details.
+var ProblemDetails bool
+
+// Problem is the RFC 7807 representation of an error, with its code and
+// details, the resource and id it is about and the request ID as extension
+// members
+type Problem struct {
+	Type      string                 `json:"type"`
+	Title     string                 `json:"title"`
+	Status    int                    `json:"status"`
+	Detail    string                 `json:"detail,omitempty"`
+	Instance  string                 `json:"instance,omitempty"`
+	Code      string                 `json:"code,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	Resource  string                 `json:"resource,omitempty"`
+	ID        string                 `json:"id,omitempty"`
+	RequestID string                 `json:"request_id,omitempty"`
+}
+
+// ProblemType is the URI identifying the problem type of errors of type t,
+// such as urn:swapi:problem:not-found
+func ProblemType(t errors.Type) string {
+	return "urn:swapi:problem:" + strings.ReplaceAll(strings.ToLower(string(t)), "_", "-")
+}
+
+// NewProblem describes err, served with status in response to r
+func NewProblem(r *http.Request, status int, err error) Problem {
+	e := errors.NewInternal()
+	stderrors.As(err, &e)
+
+	return Problem{
+		Type:     ProblemType(e.Type),
+		Title:    e.Type.Title(),
+		Status:   status,
+		Detail:   e.Message,
+		Instance: r.URL.Path,
+		Code:     e.Code,
+		Details:  e.Details,
+		Resource: e.Resource,
+		ID:       e.ID,
+	}
+}
+
+// wantsProblem tells whether errors in response to r are served as problem
+// details: when configured to, or when the client prefers them to plain JSON
+func wantsProblem(r *http.Request) bool {
+	if ProblemDetails {
+		return true
+	}
+
+	accept := r.Header.Get("Accept")
+	problem := acceptQuality(accept, ProblemContentType)
+
+	return problem > 0 && problem >= acceptQuality(accept, "application/json")
+}
+
+// acceptQuality returns the quality given to mediaType by an Accept header,
+// 0 when it isn't listed. Wildcards aren't taken into account, so that only
+// clients naming problem details get them.
+func acceptQuality(accept string, mediaType string) float64 {
+	quality := 0.0
+
+	for _, accepted := range strings.Split(accept, ",") {
+		params := strings.Split(accepted, ";")
+
+		if !strings.EqualFold(strings.TrimSpace(params[0]), mediaType) {
+			continue
+		}
+
+		if q := qualityParam(params[1:]); q > quality {
+			quality = q
+		}
+	}
+
+	return quality
+}
+
+// qualityParam returns the q parameter among the parameters of an accepted
+// media range, 1 when it's missing or invalid
+func qualityParam(params []string) float64 {
+	q := 1.0
+
+	for _, param := range params {
+		name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+
+		if found && strings.EqualFold(name, "q") {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+	}
+
+	return q
+}
+
+// writeProblem serves err as problem details
+func writeProblem(rw http.ResponseWriter, r *http.Request, status int, err error) {
+	problem := NewProblem(r, status, err)
+	problem.RequestID = rw.Header().Get(logging.RequestIDHeader)
+
+	rw.Header().Set("Content-Type", ProblemContentType)
+	rw.WriteHeader(status)
+	rw.Write(utils.ToJSON(problem))
+}
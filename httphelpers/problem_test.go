@@ -0,0 +1,154 @@
+package httphelpers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"swapi/errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWantsProblem(t *testing.T) {
+
+	type TestCase struct {
+		Name           string
+		Accept         string
+		ProblemDetails bool
+		Expected       bool
+	}
+
+	testCases := []TestCase{
+		{Name: "No Accept header", Accept: "", Expected: false},
+		{Name: "JSON", Accept: "application/json", Expected: false},
+		{Name: "Wildcard", Accept: "*/*", Expected: false},
+		{Name: "Problem details", Accept: "application/problem+json", Expected: true},
+		{Name: "Case insensitive", Accept: "Application/Problem+JSON", Expected: true},
+		{Name: "Problem details preferred", Accept: "application/json;q=0.5, application/problem+json", Expected: true},
+		{Name: "JSON preferred", Accept: "application/problem+json;q=0.5, application/json", Expected: false},
+		{Name: "Same quality", Accept: "application/json, application/problem+json", Expected: true},
+		{Name: "Refused", Accept: "application/problem+json;q=0", Expected: false},
+		{Name: "Configured", Accept: "application/json", ProblemDetails: true, Expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			ProblemDetails = tc.ProblemDetails
+			defer func() { ProblemDetails = false }()
+
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/people/1", nil)
+			r.Header.Set("Accept", tc.Accept)
+
+			assert.Equal(t, tc.Expected, wantsProblem(r))
+		})
+	}
+}
+
+func TestNewProblem(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/people/99?fields=name", nil)
+
+	assert.Equal(t, Problem{
+		Type:     "urn:swapi:problem:not-found",
+		Title:    "Not Found",
+		Status:   http.StatusNotFound,
+		Detail:   "resource: people with id: 99 not found",
+		Instance: "/api/v1/people/99",
+		Code:     "resource_not_found",
+		Resource: "people",
+		ID:       "99",
+	}, NewProblem(r, http.StatusNotFound, errors.NewNotFound("people", "99")))
+
+	assert.Equal(t, Problem{
+		Type:     "urn:swapi:problem:upstream-unavailable",
+		Title:    "Upstream Unavailable",
+		Status:   http.StatusServiceUnavailable,
+		Detail:   "Upstream unavailable. Try again later.",
+		Instance: "/api/v1/people/99",
+		Code:     "upstream_circuit_open",
+	}, NewProblem(r, http.StatusServiceUnavailable, errors.NewUpstreamUnavailable()))
+
+	assert.Equal(t, Problem{
+		Type:     "urn:swapi:problem:internal-server-error",
+		Title:    "Internal Server Error",
+		Status:   http.StatusInternalServerError,
+		Detail:   "Internal server error.",
+		Instance: "/api/v1/people/99",
+		Code:     "internal_error",
+	}, NewProblem(r, http.StatusInternalServerError, http.ErrHandlerTimeout), "untyped errors aren't leaked")
+}
+
+func TestError(t *testing.T) {
+
+	type TestCase struct {
+		Name                 string
+		Err                  error
+		Accept               string
+		ExpectedStatusCode   int
+		ExpectedRetryAfter   string
+		ExpectedResponseBody string
+	}
+
+	testCases := []TestCase{
+		{
+			Name:                 "Typed",
+			Err:                  errors.NewTooManyRequests(),
+			ExpectedStatusCode:   http.StatusTooManyRequests,
+			ExpectedResponseBody: `{"type":"TOO_MANY_REQUESTS","code":"rate_limited","message":"Too many requests. Try again later."}`,
+		},
+		{
+			Name:                 "Bad request isn't downgraded",
+			Err:                  errors.NewBadRequest("invalid page"),
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedResponseBody: `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid page"}`,
+		},
+		{
+			Name:                 "Untyped",
+			Err:                  stderrors.New("dial tcp: connection refused"),
+			ExpectedStatusCode:   http.StatusInternalServerError,
+			ExpectedResponseBody: `{"type":"INTERNAL_SERVER_ERROR","code":"internal_error","message":"Internal server error."}`,
+		},
+		{
+			Name:                 "Problem details",
+			Err:                  errors.NewBadGateway(),
+			Accept:               ProblemContentType,
+			ExpectedStatusCode:   http.StatusBadGateway,
+			ExpectedResponseBody: `{"type":"urn:swapi:problem:bad-gateway","title":"Bad Gateway","status":502,"detail":"Bad gateway. The upstream server failed to handle the request.","instance":"/api/v1/people/1","code":"upstream_error"}`,
+		},
+		{
+			Name:                 "Retry after",
+			Err:                  errors.NewUpstreamRateLimited(1500 * time.Millisecond),
+			ExpectedStatusCode:   http.StatusServiceUnavailable,
+			ExpectedRetryAfter:   "2",
+			ExpectedResponseBody: `{"type":"SERVICE_UNAVAILABLE","code":"upstream_rate_limited","message":"Service unavailable. The upstream server is rate limiting requests, try again later."}`,
+		},
+		{
+			Name:                 "Cause isn't leaked",
+			Err:                  errors.NewInternal().WithCause(stderrors.New("decoding body: unexpected end of JSON input")),
+			ExpectedStatusCode:   http.StatusInternalServerError,
+			ExpectedResponseBody: `{"type":"INTERNAL_SERVER_ERROR","code":"internal_error","message":"Internal server error."}`,
+		},
+		{
+			Name:                 "Details",
+			Err:                  errors.NewBadRequest("invalid page").WithDetail("parameter", "page"),
+			Accept:               ProblemContentType,
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedResponseBody: `{"type":"urn:swapi:problem:bad-request","title":"Bad Request","status":400,"detail":"Bad request. Reason: invalid page","instance":"/api/v1/people/1","code":"invalid_request","details":{"parameter":"page"}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/people/1", nil)
+			r.Header.Set("Accept", tc.Accept)
+
+			recorder := httptest.NewRecorder()
+			Error(recorder, r, tc.Err)
+
+			assert.Equal(t, tc.ExpectedStatusCode, recorder.Code)
+			assert.Equal(t, tc.ExpectedRetryAfter, recorder.Header().Get("Retry-After"))
+			assert.JSONEq(t, tc.ExpectedResponseBody, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,339 @@
+package httphelpers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"swapi/errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Media types responses can be rendered as, besides JSON
+const (
+	NDJSONContentType = "application/x-ndjson"
+	CSVContentType    = "text/csv; charset=utf-8"
+	YAMLContentType   = "application/yaml"
+)
+
+// format renders the JSON encoding of a response body as one media type
+type format struct {
+	// mediaTypes the format is accepted as, the first one is served
+	mediaTypes  []string
+	contentType string
+	// listsOnly formats can only render lists, bodies with results
+	listsOnly bool
+	// render gets the items of lists, nil for other bodies
+	render func(body []byte, items []json.RawMessage, pretty bool) ([]byte, error)
+}
+
+// formats in order of preference, JSON being the default
+var formats = []format{
+	{mediaTypes: []string{"application/json"}, contentType: "application/json", render: renderJSON},
+	{mediaTypes: []string{NDJSONContentType, "application/ndjson"}, contentType: NDJSONContentType, listsOnly: true, render: renderNDJSON},
+	{mediaTypes: []string{"text/csv"}, contentType: CSVContentType, render: renderCSV},
+	{mediaTypes: []string{YAMLContentType, "application/x-yaml", "text/yaml"}, contentType: YAMLContentType, render: renderYAML},
+}
+
+// Render serves data with the given status in the format the client prefers,
+// going by its Accept header, JSON when it has none. JSON is indented with
+// pretty=true. Clients accepting none of the formats data can be rendered as
+// get a 406.
+func Render(rw http.ResponseWriter, r *http.Request, status int, data interface{}) {
+	rw.Header().Add("Vary", "Accept")
+
+	pretty, err := prettyParam(r)
+
+	if err != nil {
+		Error(rw, r, err)
+		return
+	}
+
+	body, err := json.Marshal(data)
+
+	if err != nil {
+		Error(rw, r, errors.NewInternal().WithCause(err))
+		return
+	}
+
+	items, isList := listItems(body)
+
+	var offers []format
+
+	for _, f := range formats {
+		if isList || !f.listsOnly {
+			offers = append(offers, f)
+		}
+	}
+
+	f, ok := negotiate(r.Header.Get("Accept"), offers)
+
+	if !ok {
+		supported := make([]string, 0, len(offers))
+
+		for _, offer := range offers {
+			supported = append(supported, offer.mediaTypes[0])
+		}
+
+		Error(rw, r, errors.NewNotAcceptable(supported...))
+		return
+	}
+
+	out, err := f.render(body, items, pretty)
+
+	if err != nil {
+		Error(rw, r, errors.NewInternal().WithCause(fmt.Errorf("rendering %s: %w", f.mediaTypes[0], err)))
+		return
+	}
+
+	rw.Header().Set("Content-Type", f.contentType)
+	rw.WriteHeader(status)
+	rw.Write(out)
+}
+
+func prettyParam(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get("pretty")
+
+	if value == "" {
+		return false, nil
+	}
+
+	pretty, err := strconv.ParseBool(value)
+
+	if err != nil {
+		return false, errors.NewBadRequest("invalid pretty")
+	}
+
+	return pretty, nil
+}
+
+// listItems returns the results of list bodies, and whether body is one
+func listItems(body []byte) ([]json.RawMessage, bool) {
+	var list struct {
+		Results *[]json.RawMessage `json:"results"`
+	}
+
+	if json.Unmarshal(body, &list) != nil || list.Results == nil {
+		return nil, false
+	}
+
+	return *list.Results, true
+}
+
+// negotiate picks the format the Accept header prefers, the first of offers
+// on ties. A missing header accepts anything.
+func negotiate(accept string, offers []format) (format, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0], true
+	}
+
+	var best format
+	bestQuality := 0.0
+
+	for _, offer := range offers {
+		for _, mediaType := range offer.mediaTypes {
+			if q := rangeQuality(accept, mediaType); q > bestQuality {
+				best, bestQuality = offer, q
+			}
+		}
+	}
+
+	return best, bestQuality > 0
+}
+
+// rangeQuality returns the quality an Accept header gives to mediaType, taken
+// from the most specific media range matching it, wildcards included
+func rangeQuality(accept string, mediaType string) float64 {
+	quality, specificity := 0.0, -1
+	mainType, _, _ := strings.Cut(mediaType, "/")
+
+	for _, accepted := range strings.Split(accept, ",") {
+		params := strings.Split(accepted, ";")
+		mediaRange := strings.ToLower(strings.TrimSpace(params[0]))
+
+		var s int
+
+		switch {
+		case mediaRange == mediaType:
+			s = 2
+		case mediaRange == mainType+"/*":
+			s = 1
+		case mediaRange == "*/*":
+			s = 0
+		default:
+			continue
+		}
+
+		if q := qualityParam(params[1:]); s > specificity || (s == specificity && q > quality) {
+			quality, specificity = q, s
+		}
+	}
+
+	return quality
+}
+
+func renderJSON(body []byte, items []json.RawMessage, pretty bool) ([]byte, error) {
+	if !pretty {
+		return body, nil
+	}
+
+	var indented bytes.Buffer
+
+	if err := json.Indent(&indented, body, "", "  "); err != nil {
+		return nil, err
+	}
+
+	return indented.Bytes(), nil
+}
+
+// renderNDJSON writes every item of a list on its own line
+func renderNDJSON(body []byte, items []json.RawMessage, pretty bool) ([]byte, error) {
+	var out bytes.Buffer
+
+	for _, item := range items {
+		out.Write(item)
+		out.WriteByte('\n')
+	}
+
+	return out.Bytes(), nil
+}
+
+// renderCSV writes a row per item of lists, a single one otherwise, with the
+// fields as columns in the order of the JSON encoding. Nested values are
+// written as JSON.
+func renderCSV(body []byte, items []json.RawMessage, pretty bool) ([]byte, error) {
+	rows := items
+
+	if rows == nil {
+		rows = []json.RawMessage{body}
+	}
+
+	var columns []string
+	seen := map[string]bool{}
+	records := make([]map[string]json.RawMessage, 0, len(rows))
+
+	for _, row := range rows {
+		keys, values, err := objectFields(row)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range keys {
+			if !seen[key] {
+				seen[key] = true
+				columns = append(columns, key)
+			}
+		}
+
+		records = append(records, values)
+	}
+
+	var out bytes.Buffer
+	w := csv.NewWriter(&out)
+
+	if len(columns) > 0 {
+		w.Write(columns)
+	}
+
+	for _, values := range records {
+		record := make([]string, len(columns))
+
+		for i, column := range columns {
+			record[i] = csvCell(values[column])
+		}
+
+		w.Write(record)
+	}
+
+	w.Flush()
+
+	return out.Bytes(), w.Error()
+}
+
+// objectFields decodes a JSON object keeping the order of its keys
+func objectFields(raw json.RawMessage) ([]string, map[string]json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, nil, fmt.Errorf("csv rows must be objects")
+	}
+
+	var keys []string
+	values := map[string]json.RawMessage{}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key := token.(string)
+
+		var value json.RawMessage
+
+		if err := decoder.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+
+		keys = append(keys, key)
+		values[key] = value
+	}
+
+	return keys, values, nil
+}
+
+func csvCell(value json.RawMessage) string {
+	if len(value) == 0 || string(value) == "null" {
+		return ""
+	}
+
+	var s string
+
+	if json.Unmarshal(value, &s) == nil {
+		return s
+	}
+
+	return string(value)
+}
+
+// renderYAML goes through a YAML node decoded from the JSON encoding, so that
+// fields keep their JSON names and order
+func renderYAML(body []byte, items []json.RawMessage, pretty bool) ([]byte, error) {
+	var node yaml.Node
+
+	if err := yaml.Unmarshal(body, &node); err != nil {
+		return nil, err
+	}
+
+	blockStyle(&node)
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(&node); err != nil {
+		return nil, err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// blockStyle drops the flow style and quotes of the JSON a node was decoded
+// from, the encoder quotes strings that need it
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
@@ -0,0 +1,180 @@
+package httphelpers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"swapi/errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type renderItem struct {
+	Name   string   `json:"name"`
+	Height int      `json:"height"`
+	Films  []string `json:"films"`
+	Note   *string  `json:"note"`
+}
+
+type renderList struct {
+	Count   int          `json:"count"`
+	Results []renderItem `json:"results"`
+}
+
+func TestRender(t *testing.T) {
+
+	item := renderItem{Name: "Luke Skywalker", Height: 172, Films: []string{"1", "2"}}
+	list := renderList{Count: 2, Results: []renderItem{item, {Name: "Leia, Princess", Height: 150}}}
+
+	type TestCase struct {
+		Name                 string
+		Data                 interface{}
+		URL                  string
+		Accept               string
+		ExpectedStatusCode   int
+		ExpectedContentType  string
+		ExpectedResponseBody string
+	}
+
+	testCases := []TestCase{
+		{
+			Name:                 "JSON by default",
+			Data:                 item,
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedContentType:  "application/json",
+			ExpectedResponseBody: `{"name":"Luke Skywalker","height":172,"films":["1","2"],"note":null}`,
+		},
+		{
+			Name:                 "Any",
+			Data:                 item,
+			Accept:               "*/*",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedContentType:  "application/json",
+			ExpectedResponseBody: `{"name":"Luke Skywalker","height":172,"films":["1","2"],"note":null}`,
+		},
+		{
+			Name:                 "Pretty JSON",
+			Data:                 renderList{Count: 0, Results: []renderItem{}},
+			URL:                  "/?pretty=true",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedContentType:  "application/json",
+			ExpectedResponseBody: "{\n  \"count\": 0,\n  \"results\": []\n}",
+		},
+		{
+			Name:                 "Invalid pretty",
+			Data:                 item,
+			URL:                  "/?pretty=very",
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedContentType:  "application/json",
+			ExpectedResponseBody: `{"type":"BAD_REQUEST","code":"invalid_request","message":"Bad request. Reason: invalid pretty"}`,
+		},
+		{
+			Name:                 "NDJSON list",
+			Data:                 list,
+			Accept:               "application/x-ndjson",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedContentType:  NDJSONContentType,
+			ExpectedResponseBody: "{\"name\":\"Luke Skywalker\",\"height\":172,\"films\":[\"1\",\"2\"],\"note\":null}\n{\"name\":\"Leia, Princess\",\"height\":150,\"films\":null,\"note\":null}\n",
+		},
+		{
+			Name:                 "NDJSON isn't for single resources",
+			Data:                 item,
+			Accept:               "application/x-ndjson",
+			ExpectedStatusCode:   http.StatusNotAcceptable,
+			ExpectedContentType:  "application/json",
+			ExpectedResponseBody: `{"type":"NOT_ACCEPTABLE","code":"not_acceptable","message":"Not acceptable. Supported media types: application/json, text/csv, application/yaml","details":{"supported":["application/json","text/csv","application/yaml"]}}`,
+		},
+		{
+			Name:                 "CSV list",
+			Data:                 list,
+			Accept:               "text/csv",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedContentType:  CSVContentType,
+			ExpectedResponseBody: "name,height,films,note\nLuke Skywalker,172,\"[\"\"1\"\",\"\"2\"\"]\",\n\"Leia, Princess\",150,,\n",
+		},
+		{
+			Name:                 "CSV single resource",
+			Data:                 item,
+			Accept:               "text/csv",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedContentType:  CSVContentType,
+			ExpectedResponseBody: "name,height,films,note\nLuke Skywalker,172,\"[\"\"1\"\",\"\"2\"\"]\",\n",
+		},
+		{
+			Name:                 "YAML",
+			Data:                 item,
+			Accept:               "application/yaml",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedContentType:  YAMLContentType,
+			ExpectedResponseBody: "name: Luke Skywalker\nheight: 172\nfilms:\n  - \"1\"\n  - \"2\"\nnote: null\n",
+		},
+		{
+			Name:                 "Preferred by quality",
+			Data:                 item,
+			Accept:               "application/json;q=0.5, text/yaml",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedContentType:  YAMLContentType,
+			ExpectedResponseBody: "name: Luke Skywalker\nheight: 172\nfilms:\n  - \"1\"\n  - \"2\"\nnote: null\n",
+		},
+		{
+			Name:                 "Most specific range wins",
+			Data:                 item,
+			Accept:               "application/*;q=0.2, application/yaml;q=0, text/*",
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedContentType:  CSVContentType,
+			ExpectedResponseBody: "name,height,films,note\nLuke Skywalker,172,\"[\"\"1\"\",\"\"2\"\"]\",\n",
+		},
+		{
+			Name:                 "Unsupported",
+			Data:                 list,
+			Accept:               "application/xml",
+			ExpectedStatusCode:   http.StatusNotAcceptable,
+			ExpectedContentType:  "application/json",
+			ExpectedResponseBody: `{"type":"NOT_ACCEPTABLE","code":"not_acceptable","message":"Not acceptable. Supported media types: application/json, application/x-ndjson, text/csv, application/yaml","details":{"supported":["application/json","application/x-ndjson","text/csv","application/yaml"]}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			url := tc.URL
+
+			if url == "" {
+				url = "/"
+			}
+
+			r := httptest.NewRequest(http.MethodGet, url, nil)
+
+			if tc.Accept != "" {
+				r.Header.Set("Accept", tc.Accept)
+			}
+
+			recorder := httptest.NewRecorder()
+			Render(recorder, r, http.StatusOK, tc.Data)
+
+			// the headers as they were when the status was written, those
+			// set later aren't sent
+			response := recorder.Result()
+
+			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.ExpectedContentType, response.Header.Get("Content-Type"))
+			assert.Equal(t, "Accept", response.Header.Get("Vary"))
+			assert.Equal(t, tc.ExpectedResponseBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHeadersBeforeStatus(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	recorder := httptest.NewRecorder()
+	JSON(recorder, http.StatusServiceUnavailable, map[string]string{"status": "failing"})
+	assert.Equal(t, "application/json", recorder.Result().Header.Get("Content-Type"))
+
+	recorder = httptest.NewRecorder()
+	OK(recorder, r, map[string]string{"status": "ok"})
+	assert.Equal(t, "application/json", recorder.Result().Header.Get("Content-Type"))
+
+	recorder = httptest.NewRecorder()
+	Error(recorder, r, errors.NewNotFound("people", "1"))
+	assert.Equal(t, "application/json", recorder.Result().Header.Get("Content-Type"))
+}
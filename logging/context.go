@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync/atomic"
+)
+
+// RequestIDHeader carries the request ID, both ways
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+type upstreamCallsKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, if any
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+
+	return id
+}
+
+// NewRequestID returns a random 128 bit hex ID
+func NewRequestID() string {
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(b)
+}
+
+// WithUpstreamCalls returns a copy of ctx counting the upstream calls made on
+// its behalf, and the counter
+func WithUpstreamCalls(ctx context.Context) (context.Context, *int64) {
+	counter := new(int64)
+
+	return context.WithValue(ctx, upstreamCallsKey{}, counter), counter
+}
+
+// AddUpstreamCall counts an upstream call made on behalf of ctx
+func AddUpstreamCall(ctx context.Context) {
+	if counter, ok := ctx.Value(upstreamCallsKey{}).(*int64); ok {
+		atomic.AddInt64(counter, 1)
+	}
+}
+
+type servedErrorKey struct{}
+
+// WithServedError returns a copy of ctx where the error served in response to
+// its request can be recorded, and where it is recorded
+func WithServedError(ctx context.Context) (context.Context, *error) {
+	served := new(error)
+
+	return context.WithValue(ctx, servedErrorKey{}, served), served
+}
+
+// SetServedError records the error served in response to the request of ctx
+func SetServedError(ctx context.Context, err error) {
+	if served, ok := ctx.Value(servedErrorKey{}).(*error); ok {
+		*served = err
+	}
+}
@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// ParseLevel reads one of debug, info, warn or error
+func ParseLevel(name string) (Level, error) {
+	for level, levelName := range levelNames {
+		if levelName == name {
+			return level, nil
+		}
+	}
+
+	return LevelInfo, fmt.Errorf("unknown log level %q", name)
+}
+
+// Fields are the structured values of a log entry
+type Fields map[string]interface{}
+
+// Logger writes one JSON object per line for every entry at or above its
+// level. A nil *Logger discards everything.
+type Logger struct {
+	level Level
+	now   func() time.Time
+
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func New(out io.Writer, level Level) *Logger {
+	return &Logger{
+		level: level,
+		now:   time.Now,
+		out:   out,
+	}
+}
+
+func (l *Logger) Enabled(level Level) bool {
+	return l != nil && level >= l.level
+}
+
+func (l *Logger) Log(level Level, msg string, fields Fields) {
+	if !l.Enabled(level) {
+		return
+	}
+
+	entry := make(Fields, len(fields)+3)
+
+	for key, value := range fields {
+		entry[key] = value
+	}
+
+	entry["time"] = l.now().UTC().Format(time.RFC3339Nano)
+	entry["level"] = level.String()
+	entry["msg"] = msg
+
+	line, err := json.Marshal(entry)
+
+	if err != nil {
+		line, _ = json.Marshal(Fields{"time": entry["time"], "level": "error", "msg": "unloggable entry", "error": err.Error()})
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.out.Write(append(line, '\n'))
+}
+
+func (l *Logger) Debug(msg string, fields Fields) {
+	l.Log(LevelDebug, msg, fields)
+}
+
+func (l *Logger) Info(msg string, fields Fields) {
+	l.Log(LevelInfo, msg, fields)
+}
+
+func (l *Logger) Warn(msg string, fields Fields) {
+	l.Log(LevelWarn, msg, fields)
+}
+
+func (l *Logger) Error(msg string, fields Fields) {
+	l.Log(LevelError, msg, fields)
+}
@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger(t *testing.T) {
+	newTestLogger := func(level Level) (*Logger, *bytes.Buffer) {
+		out := &bytes.Buffer{}
+		logger := New(out, level)
+		logger.now = func() time.Time {
+			return time.Date(2022, 5, 4, 12, 0, 0, 0, time.UTC)
+		}
+
+		return logger, out
+	}
+
+	t.Run("JSON lines", func(t *testing.T) {
+		logger, out := newTestLogger(LevelInfo)
+
+		logger.Info("request", Fields{"status": 200})
+		logger.Error("failed", nil)
+
+		assert.Equal(t, `{"level":"info","msg":"request","status":200,"time":"2022-05-04T12:00:00Z"}
+{"level":"error","msg":"failed","time":"2022-05-04T12:00:00Z"}
+`, out.String())
+	})
+
+	t.Run("Level", func(t *testing.T) {
+		logger, out := newTestLogger(LevelWarn)
+
+		logger.Debug("debug", nil)
+		logger.Info("info", nil)
+		logger.Warn("warn", nil)
+
+		assert.Equal(t, `{"level":"warn","msg":"warn","time":"2022-05-04T12:00:00Z"}`+"\n", out.String())
+		assert.False(t, logger.Enabled(LevelInfo))
+		assert.True(t, logger.Enabled(LevelError))
+	})
+
+	t.Run("Unloggable fields", func(t *testing.T) {
+		logger, out := newTestLogger(LevelInfo)
+
+		logger.Info("request", Fields{"channel": make(chan int)})
+
+		assert.Contains(t, out.String(), `"msg":"unloggable entry"`)
+	})
+
+	t.Run("Nil logger", func(t *testing.T) {
+		var logger *Logger
+
+		assert.NotPanics(t, func() {
+			logger.Error("ignored", nil)
+		})
+	})
+}
+
+func TestParseLevel(t *testing.T) {
+	for _, name := range []string{"debug", "info", "warn", "error"} {
+		level, err := ParseLevel(name)
+
+		assert.Nil(t, err)
+		assert.Equal(t, name, level.String())
+	}
+
+	_, err := ParseLevel("loud")
+
+	assert.EqualError(t, err, `unknown log level "loud"`)
+}
+
+func TestContext(t *testing.T) {
+	t.Run("Request ID", func(t *testing.T) {
+		assert.Equal(t, "", RequestID(context.Background()))
+		assert.Equal(t, "abc", RequestID(WithRequestID(context.Background(), "abc")))
+		assert.Len(t, NewRequestID(), 32)
+		assert.NotEqual(t, NewRequestID(), NewRequestID())
+	})
+
+	t.Run("Upstream calls", func(t *testing.T) {
+		AddUpstreamCall(context.Background())
+
+		ctx, calls := WithUpstreamCalls(context.Background())
+
+		AddUpstreamCall(ctx)
+		AddUpstreamCall(ctx)
+
+		assert.Equal(t, int64(2), *calls)
+	})
+}
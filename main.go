@@ -1,11 +1,35 @@
 package main
 
-import "swapi/api"
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"swapi/api"
+	"swapi/config"
+	"syscall"
+)
 
 func main() {
-	api := api.New()
+	cfg, err := config.Load(os.Args[1:], os.Getenv)
 
-	if err := api.Run(); err != nil {
-		panic(err)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-ctx.Done()
+		// a second signal kills the process without waiting for the drain
+		stop()
+	}()
+
+	api := api.New(cfg)
+
+	if err := api.Run(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,275 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are the upper bounds, in seconds, of latency histograms
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Default is the registry exposed on /metrics
+var Default = NewRegistry()
+
+type collector interface {
+	write(w *bufio.Writer)
+}
+
+// Registry holds metrics in registration order and writes them in the
+// Prometheus text format
+type Registry struct {
+	mu      sync.Mutex
+	metrics []collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.metrics = append(r.metrics, c)
+}
+
+// Write writes every metric of the registry to w
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	metrics := append([]collector(nil), r.metrics...)
+	r.mu.Unlock()
+
+	buf := bufio.NewWriter(w)
+
+	for _, m := range metrics {
+		m.write(buf)
+	}
+
+	return buf.Flush()
+}
+
+// Handler serves the metrics of r
+func Handler(r *Registry) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", ContentType)
+		r.Write(rw)
+	}
+}
+
+// vec is the set of series of a metric, one per combination of label values
+type vec struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	series map[string][]string
+}
+
+func newVec(name string, help string, labels []string) vec {
+	return vec{name: name, help: help, labels: labels, series: map[string][]string{}}
+}
+
+// key identifies the series of the given label values, storing them on first
+// use. It must be called with mu held.
+func (v *vec) key(values []string) string {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", v.name, len(v.labels), len(values)))
+	}
+
+	key := strings.Join(values, "\xff")
+
+	if _, ok := v.series[key]; !ok {
+		v.series[key] = append([]string(nil), values...)
+	}
+
+	return key
+}
+
+// sortedKeys returns the series keys in a stable order. It must be called
+// with mu held.
+func (v *vec) sortedKeys() []string {
+	keys := make([]string, 0, len(v.series))
+
+	for key := range v.series {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (v *vec) header(w *bufio.Writer, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", v.name, helpEscaper.Replace(v.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", v.name, kind)
+}
+
+// labelPairs formats the labels of a series, with an optional extra pair such
+// as a histogram's le
+func (v *vec) labelPairs(values []string, extra ...string) string {
+	pairs := make([]string, 0, len(values)+1)
+
+	for i, value := range values {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, v.labels[i], labelEscaper.Replace(value)))
+	}
+
+	if len(extra) == 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extra[0], extra[1]))
+	}
+
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// Counter is a monotonically increasing value per combination of labels
+type Counter struct {
+	vec
+	values map[string]float64
+}
+
+// NewCounter registers a counter with the given label names
+func (r *Registry) NewCounter(name string, help string, labels ...string) *Counter {
+	c := &Counter{vec: newVec(name, help, labels), values: map[string]float64{}}
+	r.register(c)
+
+	return c
+}
+
+// Inc adds one to the series of the given label values
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+func (c *Counter) Add(delta float64, values ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.values[c.key(values)] += delta
+}
+
+// Value returns the current value of the series of the given label values
+func (c *Counter) Value(values ...string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.values[strings.Join(values, "\xff")]
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.header(w, "counter")
+
+	for _, key := range c.sortedKeys() {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, c.labelPairs(c.series[key]), formatFloat(c.values[key]))
+	}
+}
+
+// Histogram counts observations into cumulative buckets per combination of
+// labels
+type Histogram struct {
+	vec
+	buckets []float64
+	values  map[string]*histogramValue
+}
+
+type histogramValue struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram registers a histogram with the given bucket upper bounds, in
+// increasing order, and label names
+func (r *Registry) NewHistogram(name string, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{vec: newVec(name, help, labels), buckets: buckets, values: map[string]*histogramValue{}}
+	r.register(h)
+
+	return h
+}
+
+// Observe records value in the series of the given label values
+func (h *Histogram) Observe(value float64, values ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := h.key(values)
+	v, ok := h.values[key]
+
+	if !ok {
+		v = &histogramValue{counts: make([]uint64, len(h.buckets))}
+		h.values[key] = v
+	}
+
+	for i, bound := range h.buckets {
+		if value <= bound {
+			v.counts[i]++
+		}
+	}
+
+	v.count++
+	v.sum += value
+}
+
+// Count returns how many values were observed in the series of the given
+// label values
+func (h *Histogram) Count(values ...string) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if v, ok := h.values[strings.Join(values, "\xff")]; ok {
+		return v.count
+	}
+
+	return 0
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.header(w, "histogram")
+
+	for _, key := range h.sortedKeys() {
+		labels, v := h.series[key], h.values[key]
+
+		for i, bound := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(labels, "le", formatFloat(bound)), v.counts[i])
+		}
+
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(labels, "le", "+Inf"), v.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labelPairs(labels), formatFloat(v.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labelPairs(labels), v.count)
+	}
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
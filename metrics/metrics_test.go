@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+
+	requests := registry.NewCounter("requests_total", "Requests served.", "route", "code")
+	latency := registry.NewHistogram("latency_seconds", "Request latency.", []float64{0.1, 1}, "route")
+	up := registry.NewCounter("up_total", "Help with a \\ and a\nnew line.")
+
+	requests.Inc("/people/{id}", "200")
+	requests.Inc("/people/{id}", "200")
+	requests.Add(0.5, `/a "quoted"\path`, "404")
+	latency.Observe(0.05, "/people")
+	latency.Observe(0.5, "/people")
+	latency.Observe(2, "/people")
+	up.Inc()
+
+	var out bytes.Buffer
+
+	assert.Nil(t, registry.Write(&out))
+	assert.Equal(t, `# HELP requests_total Requests served.
+# TYPE requests_total counter
+requests_total{route="/a \"quoted\"\\path",code="404"} 0.5
+requests_total{route="/people/{id}",code="200"} 2
+# HELP latency_seconds Request latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/people",le="0.1"} 1
+latency_seconds_bucket{route="/people",le="1"} 2
+latency_seconds_bucket{route="/people",le="+Inf"} 3
+latency_seconds_sum{route="/people"} 2.55
+latency_seconds_count{route="/people"} 3
+# HELP up_total Help with a \\ and a\nnew line.
+# TYPE up_total counter
+up_total 1
+`, out.String())
+
+	assert.Equal(t, float64(2), requests.Value("/people/{id}", "200"))
+	assert.Equal(t, float64(0), requests.Value("/people/{id}", "500"))
+	assert.Equal(t, uint64(3), latency.Count("/people"))
+	assert.Equal(t, uint64(0), latency.Count("/films"))
+}
+
+func TestRegistryLabelValues(t *testing.T) {
+	registry := NewRegistry()
+	requests := registry.NewCounter("requests_total", "Requests served.", "route", "code")
+
+	assert.Panics(t, func() {
+		requests.Inc("/people")
+	})
+}
+
+func TestHandler(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounter("requests_total", "Requests served.").Inc()
+
+	recorder := httptest.NewRecorder()
+	Handler(registry)(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, ContentType, recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), "requests_total 1\n")
+}
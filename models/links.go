@@ -0,0 +1,141 @@
+package models
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Resources lists the SWAPI resources, as they appear in links
+var Resources = []string{"films", "people", "planets", "species", "starships", "vehicles"}
+
+// ParseLink extracts the resource and id of a SWAPI link such as
+// "https://swapi.dev/api/planets/1/"
+func ParseLink(link string) (resource string, id int, ok bool) {
+	u, err := url.Parse(link)
+
+	if err != nil || u.Host == "" {
+		return "", 0, false
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	if len(segments) < 2 {
+		return "", 0, false
+	}
+
+	resource = segments[len(segments)-2]
+	id, err = strconv.Atoi(segments[len(segments)-1])
+
+	if err != nil || id < 1 || !isResource(resource) {
+		return "", 0, false
+	}
+
+	return resource, id, true
+}
+
+// IDFromLink returns the id at the end of a SWAPI link, or 0 when link isn't
+// one
+func IDFromLink(link string) int {
+	_, id, _ := ParseLink(link)
+
+	return id
+}
+
+func isResource(name string) bool {
+	for _, resource := range Resources {
+		if resource == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SWAPI resources carry no id field, only their own url. Decoding fills ID
+// from it.
+
+func (s *Starship) UnmarshalJSON(data []byte) error {
+	type starship Starship
+
+	if err := json.Unmarshal(data, (*starship)(s)); err != nil {
+		return err
+	}
+
+	if s.ID == 0 {
+		s.ID = IDFromLink(s.URL)
+	}
+
+	return nil
+}
+
+func (p *People) UnmarshalJSON(data []byte) error {
+	type people People
+
+	if err := json.Unmarshal(data, (*people)(p)); err != nil {
+		return err
+	}
+
+	if p.ID == 0 {
+		p.ID = IDFromLink(p.URL)
+	}
+
+	return nil
+}
+
+func (f *Film) UnmarshalJSON(data []byte) error {
+	type film Film
+
+	if err := json.Unmarshal(data, (*film)(f)); err != nil {
+		return err
+	}
+
+	if f.ID == 0 {
+		f.ID = IDFromLink(f.URL)
+	}
+
+	return nil
+}
+
+func (p *Planet) UnmarshalJSON(data []byte) error {
+	type planet Planet
+
+	if err := json.Unmarshal(data, (*planet)(p)); err != nil {
+		return err
+	}
+
+	if p.ID == 0 {
+		p.ID = IDFromLink(p.URL)
+	}
+
+	return nil
+}
+
+func (s *Species) UnmarshalJSON(data []byte) error {
+	type species Species
+
+	if err := json.Unmarshal(data, (*species)(s)); err != nil {
+		return err
+	}
+
+	if s.ID == 0 {
+		s.ID = IDFromLink(s.URL)
+	}
+
+	return nil
+}
+
+func (v *Vehicle) UnmarshalJSON(data []byte) error {
+	type vehicle Vehicle
+
+	if err := json.Unmarshal(data, (*vehicle)(v)); err != nil {
+		return err
+	}
+
+	if v.ID == 0 {
+		v.ID = IDFromLink(v.URL)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLink(t *testing.T) {
+	type TestCase struct {
+		Link             string
+		ExpectedResource string
+		ExpectedID       int
+		ExpectedOK       bool
+	}
+
+	testCases := []TestCase{
+		{Link: "https://swapi.dev/api/planets/1/", ExpectedResource: "planets", ExpectedID: 1, ExpectedOK: true},
+		{Link: "http://swapi.local/api/people/42", ExpectedResource: "people", ExpectedID: 42, ExpectedOK: true},
+		{Link: "https://swapi.dev/api/starships/"},
+		{Link: "https://swapi.dev/api/planets/tatooine/"},
+		{Link: "https://swapi.dev/api/droids/2/"},
+		{Link: "https://swapi.dev/api/films/0/"},
+		{Link: "/api/films/1/"},
+		{Link: "It is a period of civil war."},
+		{Link: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Link, func(t *testing.T) {
+			resource, id, ok := ParseLink(tc.Link)
+
+			assert.Equal(t, tc.ExpectedResource, resource)
+			assert.Equal(t, tc.ExpectedID, id)
+			assert.Equal(t, tc.ExpectedOK, ok)
+		})
+	}
+}
+
+func TestUnmarshalID(t *testing.T) {
+	t.Run("From url", func(t *testing.T) {
+		var result PeopleList
+
+		err := json.Unmarshal([]byte(`{"count":2,"results":[{"name":"Luke Skywalker","url":"https://swapi.dev/api/people/1/"},{"name":"C-3PO","url":"https://swapi.dev/api/people/2/"}]}`), &result)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, result.Results[0].ID)
+		assert.Equal(t, 2, result.Results[1].ID)
+	})
+
+	t.Run("Every resource", func(t *testing.T) {
+		var starship Starship
+		var film Film
+		var planet Planet
+		var species Species
+		var vehicle Vehicle
+
+		assert.Nil(t, json.Unmarshal([]byte(`{"url":"https://swapi.dev/api/starships/9/"}`), &starship))
+		assert.Nil(t, json.Unmarshal([]byte(`{"url":"https://swapi.dev/api/films/1/"}`), &film))
+		assert.Nil(t, json.Unmarshal([]byte(`{"url":"https://swapi.dev/api/planets/2/"}`), &planet))
+		assert.Nil(t, json.Unmarshal([]byte(`{"url":"https://swapi.dev/api/species/3/"}`), &species))
+		assert.Nil(t, json.Unmarshal([]byte(`{"url":"https://swapi.dev/api/vehicles/4/"}`), &vehicle))
+
+		assert.Equal(t, []int{9, 1, 2, 3, 4}, []int{starship.ID, film.ID, planet.ID, species.ID, vehicle.ID})
+	})
+
+	t.Run("Explicit id wins", func(t *testing.T) {
+		var planet Planet
+
+		assert.Nil(t, json.Unmarshal([]byte(`{"id":5,"url":"https://swapi.dev/api/planets/2/"}`), &planet))
+		assert.Equal(t, 5, planet.ID)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		var planet Planet
+
+		assert.Error(t, json.Unmarshal([]byte(`{"name":1}`), &planet))
+	})
+}
@@ -1,6 +1,7 @@
 package models
 
 type Starship struct {
+	ID                   int      `json:"id,omitempty"`
 	Name                 string   `json:"name"`
 	Model                string   `json:"model"`
 	Class                string   `json:"starship_class"`
@@ -16,14 +17,18 @@ type Starship struct {
 	Consumables          string   `json:"consumables"`
 	Films                []string `json:"films"`
 	Pilots               []string `json:"pilots"`
+	URL                  string   `json:"url,omitempty"`
 }
 
 type Starships struct {
-	Count   int        `json:"count"`
-	Results []Starship `json:"results"`
+	Count    int        `json:"count"`
+	Next     string     `json:"next,omitempty"`
+	Previous string     `json:"previous,omitempty"`
+	Results  []Starship `json:"results"`
 }
 
 type People struct {
+	ID        int      `json:"id,omitempty"`
 	Name      string   `json:"name"`
 	BirthYear string   `json:"birth_year"`
 	EyeColor  string   `json:"eye_color"`
@@ -36,9 +41,107 @@ type People struct {
 	Films     []string `json:"films"`
 	Species   []string `json:"species"`
 	Starships []string `json:"starships"`
+	URL       string   `json:"url,omitempty"`
 }
 
 type PeopleList struct {
-	Count   int      `json:"count"`
-	Results []People `json:"results"`
+	Count    int      `json:"count"`
+	Next     string   `json:"next,omitempty"`
+	Previous string   `json:"previous,omitempty"`
+	Results  []People `json:"results"`
+}
+
+type Film struct {
+	ID           int      `json:"id,omitempty"`
+	Title        string   `json:"title"`
+	EpisodeID    int      `json:"episode_id"`
+	OpeningCrawl string   `json:"opening_crawl"`
+	Director     string   `json:"director"`
+	Producer     string   `json:"producer"`
+	ReleaseDate  string   `json:"release_date"`
+	Characters   []string `json:"characters"`
+	Planets      []string `json:"planets"`
+	Starships    []string `json:"starships"`
+	Vehicles     []string `json:"vehicles"`
+	Species      []string `json:"species"`
+	URL          string   `json:"url,omitempty"`
+}
+
+type Films struct {
+	Count    int    `json:"count"`
+	Next     string `json:"next,omitempty"`
+	Previous string `json:"previous,omitempty"`
+	Results  []Film `json:"results"`
+}
+
+type Planet struct {
+	ID             int      `json:"id,omitempty"`
+	Name           string   `json:"name"`
+	RotationPeriod string   `json:"rotation_period"`
+	OrbitalPeriod  string   `json:"orbital_period"`
+	Diameter       string   `json:"diameter"`
+	Climate        string   `json:"climate"`
+	Gravity        string   `json:"gravity"`
+	Terrain        string   `json:"terrain"`
+	SurfaceWater   string   `json:"surface_water"`
+	Population     string   `json:"population"`
+	Residents      []string `json:"residents"`
+	Films          []string `json:"films"`
+	URL            string   `json:"url,omitempty"`
+}
+
+type Planets struct {
+	Count    int      `json:"count"`
+	Next     string   `json:"next,omitempty"`
+	Previous string   `json:"previous,omitempty"`
+	Results  []Planet `json:"results"`
+}
+
+type Species struct {
+	ID              int      `json:"id,omitempty"`
+	Name            string   `json:"name"`
+	Classification  string   `json:"classification"`
+	Designation     string   `json:"designation"`
+	AverageHeight   string   `json:"average_height"`
+	AverageLifespan string   `json:"average_lifespan"`
+	EyeColors       string   `json:"eye_colors"`
+	HairColors      string   `json:"hair_colors"`
+	SkinColors      string   `json:"skin_colors"`
+	Language        string   `json:"language"`
+	Homeworld       string   `json:"homeworld"`
+	People          []string `json:"people"`
+	Films           []string `json:"films"`
+	URL             string   `json:"url,omitempty"`
+}
+
+type SpeciesList struct {
+	Count    int       `json:"count"`
+	Next     string    `json:"next,omitempty"`
+	Previous string    `json:"previous,omitempty"`
+	Results  []Species `json:"results"`
+}
+
+type Vehicle struct {
+	ID                   int      `json:"id,omitempty"`
+	Name                 string   `json:"name"`
+	Model                string   `json:"model"`
+	Class                string   `json:"vehicle_class"`
+	Manufacturer         string   `json:"manufacturer"`
+	CostInCredits        string   `json:"cost_in_credits"`
+	Length               string   `json:"length"`
+	Crew                 string   `json:"crew"`
+	Passengers           string   `json:"passengers"`
+	MaxAtmospheringSpeed string   `json:"max_atmosphering_speed"`
+	CargoCapacity        string   `json:"cargo_capacity"`
+	Consumables          string   `json:"consumables"`
+	Films                []string `json:"films"`
+	Pilots               []string `json:"pilots"`
+	URL                  string   `json:"url,omitempty"`
+}
+
+type Vehicles struct {
+	Count    int       `json:"count"`
+	Next     string    `json:"next,omitempty"`
+	Previous string    `json:"previous,omitempty"`
+	Results  []Vehicle `json:"results"`
 }
@@ -0,0 +1,246 @@
+package models
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Range is a quantity SWAPI may give either as a single value or as a range
+// such as "30-165". A single value has Min equal to Max.
+type Range struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// TypedStarship is a Starship with its numeric fields parsed. Fields SWAPI
+// reports as "unknown" or "n/a" are null.
+type TypedStarship struct {
+	ID                   int      `json:"id,omitempty"`
+	Name                 string   `json:"name"`
+	Model                string   `json:"model"`
+	Class                string   `json:"starship_class"`
+	Manufacturer         string   `json:"manufacturer"`
+	CostInCredits        *float64 `json:"cost_in_credits"`
+	Length               *float64 `json:"length"`
+	Crew                 *Range   `json:"crew"`
+	Passengers           *float64 `json:"passengers"`
+	MaxAtmospheringSpeed *float64 `json:"max_atmosphering_speed"`
+	HyperdriveRating     *float64 `json:"hyperdrive_rating"`
+	MGLT                 *float64 `json:"MGLT"`
+	CargoCapacity        *float64 `json:"cargo_capacity"`
+	ConsumablesDays      *float64 `json:"consumables_days"`
+	Films                []string `json:"films"`
+	Pilots               []string `json:"pilots"`
+	URL                  string   `json:"url,omitempty"`
+}
+
+type TypedStarships struct {
+	Count    int             `json:"count"`
+	Next     string          `json:"next,omitempty"`
+	Previous string          `json:"previous,omitempty"`
+	Results  []TypedStarship `json:"results"`
+}
+
+// TypedPeople is a People with its numeric fields parsed. Height is in
+// centimeters, mass in kilograms and the birth year in years before the
+// Battle of Yavin, negative when born after it.
+type TypedPeople struct {
+	ID           int      `json:"id,omitempty"`
+	Name         string   `json:"name"`
+	BirthYear    string   `json:"birth_year"`
+	BirthYearBBY *float64 `json:"birth_year_bby"`
+	EyeColor     string   `json:"eye_color"`
+	Gender       string   `json:"gender"`
+	HairColor    string   `json:"hair_color"`
+	Height       *float64 `json:"height"`
+	Mass         *float64 `json:"mass"`
+	SkinColor    string   `json:"skin_color"`
+	Homeworld    string   `json:"homeworld"`
+	Films        []string `json:"films"`
+	Species      []string `json:"species"`
+	Starships    []string `json:"starships"`
+	URL          string   `json:"url,omitempty"`
+}
+
+type TypedPeopleList struct {
+	Count    int           `json:"count"`
+	Next     string        `json:"next,omitempty"`
+	Previous string        `json:"previous,omitempty"`
+	Results  []TypedPeople `json:"results"`
+}
+
+func (s Starship) Typed() TypedStarship {
+	return TypedStarship{
+		ID:                   s.ID,
+		Name:                 s.Name,
+		Model:                s.Model,
+		Class:                s.Class,
+		Manufacturer:         s.Manufacturer,
+		CostInCredits:        ParseNumber(s.CostInCredits),
+		Length:               ParseNumber(s.Length),
+		Crew:                 ParseRange(s.Crew),
+		Passengers:           ParseNumber(s.Passengers),
+		MaxAtmospheringSpeed: ParseNumber(s.MaxAtmospheringSpeed),
+		HyperdriveRating:     ParseNumber(s.HyperdriveRating),
+		MGLT:                 ParseNumber(s.MGLT),
+		CargoCapacity:        ParseNumber(s.CargoCapacity),
+		ConsumablesDays:      ParseDays(s.Consumables),
+		Films:                s.Films,
+		Pilots:               s.Pilots,
+		URL:                  s.URL,
+	}
+}
+
+func (s Starships) Typed() TypedStarships {
+	results := make([]TypedStarship, len(s.Results))
+
+	for i, starship := range s.Results {
+		results[i] = starship.Typed()
+	}
+
+	return TypedStarships{
+		Count:    s.Count,
+		Next:     s.Next,
+		Previous: s.Previous,
+		Results:  results,
+	}
+}
+
+func (p People) Typed() TypedPeople {
+	return TypedPeople{
+		ID:           p.ID,
+		Name:         p.Name,
+		BirthYear:    p.BirthYear,
+		BirthYearBBY: ParseBirthYear(p.BirthYear),
+		EyeColor:     p.EyeColor,
+		Gender:       p.Gender,
+		HairColor:    p.HairColor,
+		Height:       ParseNumber(p.Height),
+		Mass:         ParseNumber(p.Mass),
+		SkinColor:    p.SkinColor,
+		Homeworld:    p.Homeworld,
+		Films:        p.Films,
+		Species:      p.Species,
+		Starships:    p.Starships,
+		URL:          p.URL,
+	}
+}
+
+func (p PeopleList) Typed() TypedPeopleList {
+	results := make([]TypedPeople, len(p.Results))
+
+	for i, people := range p.Results {
+		results[i] = people.Typed()
+	}
+
+	return TypedPeopleList{
+		Count:    p.Count,
+		Next:     p.Next,
+		Previous: p.Previous,
+		Results:  results,
+	}
+}
+
+// ParseNumber parses a SWAPI numeric string such as "1,000", "4.0" or
+// "1000km". It returns nil for "unknown", "n/a" and anything else that isn't
+// a finite decimal number, and 0 for "none".
+func ParseNumber(value string) *float64 {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	if value == "none" {
+		return float(0)
+	}
+
+	value = strings.TrimSuffix(strings.ReplaceAll(value, ",", ""), "km")
+
+	// ParseFloat also reads hex floats, "NaN" and "Inf", which aren't SWAPI
+	// numbers and the last two can't even be encoded as JSON
+	if strings.Contains(value, "x") {
+		return nil
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+		return nil
+	}
+
+	return &n
+}
+
+// ParseRange parses a single number or a range such as "30-165"
+func ParseRange(value string) *Range {
+	low, high := value, value
+
+	if i := strings.Index(value, "-"); i > 0 {
+		low, high = value[:i], value[i+1:]
+	}
+
+	min, max := ParseNumber(low), ParseNumber(high)
+
+	if min == nil || max == nil {
+		return nil
+	}
+
+	return &Range{Min: *min, Max: *max}
+}
+
+var daysPerUnit = map[string]float64{
+	"year":  365,
+	"month": 30,
+	"week":  7,
+	"day":   1,
+	"hour":  1.0 / 24,
+}
+
+// ParseDays converts a duration such as "2 years" or "1 week" to days
+func ParseDays(value string) *float64 {
+	fields := strings.Fields(strings.ToLower(value))
+
+	if len(fields) == 1 && fields[0] == "none" {
+		return float(0)
+	}
+
+	if len(fields) != 2 {
+		return nil
+	}
+
+	perUnit, ok := daysPerUnit[strings.TrimSuffix(fields[1], "s")]
+	n := ParseNumber(fields[0])
+
+	if !ok || n == nil {
+		return nil
+	}
+
+	return float(*n * perUnit)
+}
+
+// ParseBirthYear converts a birth year such as "19BBY" or "5ABY" to years
+// before the Battle of Yavin
+func ParseBirthYear(value string) *float64 {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	sign := 1.0
+
+	switch {
+	case strings.HasSuffix(value, "BBY"):
+		value = strings.TrimSuffix(value, "BBY")
+	case strings.HasSuffix(value, "ABY"):
+		value = strings.TrimSuffix(value, "ABY")
+		sign = -1
+	default:
+		return nil
+	}
+
+	n := ParseNumber(value)
+
+	if n == nil {
+		return nil
+	}
+
+	return float(sign * *n)
+}
+
+func float(n float64) *float64 {
+	return &n
+}
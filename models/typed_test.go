@@ -0,0 +1,153 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func number(n float64) *float64 {
+	return &n
+}
+
+func TestParseNumber(t *testing.T) {
+	testCases := map[string]*float64{
+		"10":        number(10),
+		"4.0":       number(4),
+		"0.5":       number(0.5),
+		"1,000":     number(1000),
+		"1000km":    number(1000),
+		" 120000 ":  number(120000),
+		"none":      number(0),
+		"unknown":   nil,
+		"n/a":       nil,
+		"":          nil,
+		"30-165":    nil,
+		"Live food": nil,
+		"NaN":       nil,
+		"Inf":       nil,
+		"-infinity": nil,
+		"1e400":     nil,
+		"0x1p3":     nil,
+	}
+
+	for value, expected := range testCases {
+		t.Run(value, func(t *testing.T) {
+			assert.Equal(t, expected, ParseNumber(value))
+		})
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	testCases := map[string]*Range{
+		"30-165":  {Min: 30, Max: 165},
+		"1,000":   {Min: 1000, Max: 1000},
+		"5":       {Min: 5, Max: 5},
+		"unknown": nil,
+		"n/a":     nil,
+		"1-":      nil,
+	}
+
+	for value, expected := range testCases {
+		t.Run(value, func(t *testing.T) {
+			assert.Equal(t, expected, ParseRange(value))
+		})
+	}
+}
+
+func TestParseDays(t *testing.T) {
+	testCases := map[string]*float64{
+		"3 years":         number(1095),
+		"1 year":          number(365),
+		"6 months":        number(180),
+		"1 week":          number(7),
+		"5 days":          number(5),
+		"12 hours":        number(0.5),
+		"none":            number(0),
+		"unknown":         nil,
+		"Live food tanks": nil,
+		"2 parsecs":       nil,
+	}
+
+	for value, expected := range testCases {
+		t.Run(value, func(t *testing.T) {
+			assert.Equal(t, expected, ParseDays(value))
+		})
+	}
+}
+
+func TestParseBirthYear(t *testing.T) {
+	testCases := map[string]*float64{
+		"19BBY":   number(19),
+		"41.9BBY": number(41.9),
+		"5ABY":    number(-5),
+		"unknown": nil,
+		"19":      nil,
+	}
+
+	for value, expected := range testCases {
+		t.Run(value, func(t *testing.T) {
+			assert.Equal(t, expected, ParseBirthYear(value))
+		})
+	}
+}
+
+func TestTyped(t *testing.T) {
+	t.Run("Starships", func(t *testing.T) {
+		starships := Starships{
+			Count: 1,
+			Next:  "next",
+			Results: []Starship{
+				{
+					Name:                 "Millennium Falcon",
+					CostInCredits:        "100000",
+					Length:               "34.37",
+					Crew:                 "4",
+					Passengers:           "6",
+					MaxAtmospheringSpeed: "1050",
+					HyperdriveRating:     "0.5",
+					MGLT:                 "75",
+					CargoCapacity:        "100000",
+					Consumables:          "2 months",
+					Pilots:               []string{"https://swapi.dev/api/people/13/"},
+				},
+			},
+		}
+
+		assert.Equal(t, TypedStarships{
+			Count: 1,
+			Next:  "next",
+			Results: []TypedStarship{
+				{
+					Name:                 "Millennium Falcon",
+					CostInCredits:        number(100000),
+					Length:               number(34.37),
+					Crew:                 &Range{Min: 4, Max: 4},
+					Passengers:           number(6),
+					MaxAtmospheringSpeed: number(1050),
+					HyperdriveRating:     number(0.5),
+					MGLT:                 number(75),
+					CargoCapacity:        number(100000),
+					ConsumablesDays:      number(60),
+					Pilots:               []string{"https://swapi.dev/api/people/13/"},
+				},
+			},
+		}, starships.Typed())
+	})
+
+	t.Run("People", func(t *testing.T) {
+		people := PeopleList{
+			Count: 1,
+			Results: []People{
+				{Name: "Jabba Desilijic Tiure", BirthYear: "600BBY", Height: "175", Mass: "1,358"},
+			},
+		}
+
+		assert.Equal(t, TypedPeopleList{
+			Count: 1,
+			Results: []TypedPeople{
+				{Name: "Jabba Desilijic Tiure", BirthYear: "600BBY", BirthYearBBY: number(600), Height: number(175), Mass: number(1358)},
+			},
+		}, people.Typed())
+	})
+}
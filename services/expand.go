@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"swapi/errors"
+	"swapi/models"
+	"swapi/tracing"
+	"sync"
+)
+
+// maxConcurrentExpansions bounds how many linked resources are fetched at
+// once for a single expansion
+const maxConcurrentExpansions = 4
+
+// ExpandError reports a linked resource that couldn't be fetched
+type ExpandError struct {
+	Field string        `json:"field"`
+	Link  string        `json:"link"`
+	Error *errors.Error `json:"error"`
+}
+
+// Expansion holds, for every expanded field, the resources its links point
+// at, in the order of the links. A link that couldn't be fetched has a nil
+// resource and an entry in Errors.
+type Expansion struct {
+	Resources map[string][]interface{}
+	Errors    []ExpandError
+}
+
+var peopleLinks = map[string]func(models.People) []string{
+	"homeworld": func(p models.People) []string { return []string{p.Homeworld} },
+	"films":     func(p models.People) []string { return p.Films },
+	"species":   func(p models.People) []string { return p.Species },
+	"starships": func(p models.People) []string { return p.Starships },
+}
+
+var starshipLinks = map[string]func(models.Starship) []string{
+	"films":  func(s models.Starship) []string { return s.Films },
+	"pilots": func(s models.Starship) []string { return s.Pilots },
+}
+
+// PeopleExpandFields lists the fields of People that can be expanded
+func PeopleExpandFields() []string {
+	return keys(peopleLinks)
+}
+
+// StarshipExpandFields lists the fields of Starship that can be expanded
+func StarshipExpandFields() []string {
+	return keys(starshipLinks)
+}
+
+// ExpandPeopleService fetches the resources linked by the given fields of people
+func ExpandPeopleService(ctx context.Context, people models.People, fields []string) Expansion {
+	ctx, span := startSpan(ctx, "ExpandPeopleService", "people", tracing.Attributes{"swapi.id": people.ID, "swapi.expand": strings.Join(fields, ",")})
+	expansion := expand(ctx, people, fields, peopleLinks)
+	span.SetAttributes(tracing.Attributes{"swapi.expand_errors": len(expansion.Errors)})
+	span.End(nil)
+
+	return expansion
+}
+
+// ExpandStarshipService fetches the resources linked by the given fields of starship
+func ExpandStarshipService(ctx context.Context, starship models.Starship, fields []string) Expansion {
+	ctx, span := startSpan(ctx, "ExpandStarshipService", "starships", tracing.Attributes{"swapi.id": starship.ID, "swapi.expand": strings.Join(fields, ",")})
+	expansion := expand(ctx, starship, fields, starshipLinks)
+	span.SetAttributes(tracing.Attributes{"swapi.expand_errors": len(expansion.Errors)})
+	span.End(nil)
+
+	return expansion
+}
+
+type expandJob struct {
+	field string
+	index int
+	link  string
+}
+
+type expandFailure struct {
+	expandJob
+	err error
+}
+
+func expand[T any](ctx context.Context, model T, fields []string, linkFields map[string]func(T) []string) Expansion {
+	expansion := Expansion{Resources: map[string][]interface{}{}}
+
+	var jobs []expandJob
+
+	for _, field := range fields {
+		linksOf, ok := linkFields[field]
+
+		if !ok {
+			continue
+		}
+
+		links := linksOf(model)
+		expansion.Resources[field] = make([]interface{}, len(links))
+
+		for i, link := range links {
+			if link != "" {
+				jobs = append(jobs, expandJob{field: field, index: i, link: link})
+			}
+		}
+	}
+
+	queue := make(chan expandJob)
+	var failures []expandFailure
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	workers := maxConcurrentExpansions
+
+	if len(jobs) < workers {
+		workers = len(jobs)
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for job := range queue {
+				resource, err := fetchLink(ctx, job.link)
+
+				mu.Lock()
+
+				if err != nil {
+					failures = append(failures, expandFailure{job, err})
+				} else {
+					expansion.Resources[job.field][job.index] = resource
+				}
+
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, job := range jobs {
+		queue <- job
+	}
+
+	close(queue)
+	wg.Wait()
+
+	// workers finish in any order, report errors in the order of the links
+	sort.Slice(failures, func(i, j int) bool {
+		if failures[i].field != failures[j].field {
+			return failures[i].field < failures[j].field
+		}
+
+		return failures[i].index < failures[j].index
+	})
+
+	for _, failure := range failures {
+		expansion.Errors = append(expansion.Errors, ExpandError{Field: failure.field, Link: failure.link, Error: typedError(failure.err)})
+	}
+
+	return expansion
+}
+
+// fetchLink fetches the resource a SWAPI link points at
+func fetchLink(ctx context.Context, link string) (interface{}, error) {
+	resource, id, ok := models.ParseLink(link)
+
+	if !ok {
+		return nil, errors.NewUpstreamContractViolation().WithCause(fmt.Errorf("invalid link %q", link))
+	}
+
+	switch resource {
+	case "films":
+		return GetFilmService(ctx, id)
+	case "people":
+		return GetPeopleService(ctx, id)
+	case "planets":
+		return GetPlanetService(ctx, id)
+	case "species":
+		return GetSpeciesService(ctx, id)
+	case "starships":
+		return GetStarshipService(ctx, id)
+	default:
+		return GetVehicleService(ctx, id)
+	}
+}
+
+func keys[T any](m map[string]T) []string {
+	result := make([]string, 0, len(m))
+
+	for key := range m {
+		result = append(result, key)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
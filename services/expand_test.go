@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"swapi/clients/swapi"
+	"swapi/errors"
+	"swapi/mockeable"
+	"swapi/models"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandPeopleService(t *testing.T) {
+	luke := models.People{
+		Name:      "Luke Skywalker",
+		Homeworld: "https://swapi.dev/api/planets/1/",
+		Films:     []string{"https://swapi.dev/api/films/1/", "https://swapi.dev/api/films/7/", "https://swapi.dev/api/films/2/"},
+		Starships: []string{"https://swapi.dev/api/starships/12/"},
+	}
+
+	t.Run("Success with failed links", func(t *testing.T) {
+		swapiMock := swapi.MockClient{
+			GetPlanetFunc: func(ctx context.Context, id int) (models.Planet, error) {
+				return models.Planet{ID: id, Name: "Tatooine"}, nil
+			},
+			GetPlanetFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+			GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+				if id == 7 {
+					return models.Film{}, errors.NewNotFound("films", "7")
+				}
+
+				return models.Film{ID: id}, nil
+			},
+			GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 3},
+		}
+
+		swapiMock.Use()
+		defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+		expansion := ExpandPeopleService(context.Background(), luke, []string{"homeworld", "films"})
+
+		assert.Equal(t, map[string][]interface{}{
+			"homeworld": {models.Planet{ID: 1, Name: "Tatooine"}},
+			"films":     {models.Film{ID: 1}, nil, models.Film{ID: 2}},
+		}, expansion.Resources)
+		assert.Equal(t, []ExpandError{
+			{Field: "films", Link: "https://swapi.dev/api/films/7/", Error: errors.NewNotFound("films", "7")},
+		}, expansion.Errors)
+	})
+
+	t.Run("Untyped and unparsable link errors", func(t *testing.T) {
+		swapiMock := swapi.MockClient{
+			GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+				if id == 1 {
+					return models.Film{}, context.DeadlineExceeded
+				}
+
+				return models.Film{}, fmt.Errorf("boom")
+			},
+			GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 2},
+		}
+
+		swapiMock.Use()
+		defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+		people := models.People{
+			Homeworld: "not a link",
+			Films:     []string{"https://swapi.dev/api/films/1/", "https://swapi.dev/api/films/2/"},
+		}
+
+		expansion := ExpandPeopleService(context.Background(), people, []string{"homeworld", "films"})
+
+		assert.Len(t, expansion.Errors, 3)
+		assert.ErrorIs(t, expansion.Errors[0].Error, errors.NewGatewayTimeout())
+		assert.ErrorIs(t, expansion.Errors[1].Error, errors.NewInternal())
+		assert.Equal(t, "homeworld", expansion.Errors[2].Field)
+		assert.ErrorIs(t, expansion.Errors[2].Error, errors.NewUpstreamContractViolation())
+
+		for _, e := range expansion.Errors {
+			assert.NotEmpty(t, e.Error.Message)
+		}
+	})
+
+	t.Run("Empty homeworld", func(t *testing.T) {
+		swapiMock := swapi.MockClient{}
+
+		swapiMock.Use()
+		defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+		expansion := ExpandPeopleService(context.Background(), models.People{}, []string{"homeworld", "species"})
+
+		assert.Equal(t, map[string][]interface{}{"homeworld": {nil}, "species": {}}, expansion.Resources)
+		assert.Empty(t, expansion.Errors)
+	})
+
+	t.Run("Bounded concurrency", func(t *testing.T) {
+		var mu sync.Mutex
+		var active, maxActive int
+
+		swapiMock := swapi.MockClient{
+			GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+				mu.Lock()
+				active++
+
+				if active > maxActive {
+					maxActive = active
+				}
+
+				mu.Unlock()
+
+				time.Sleep(5 * time.Millisecond)
+
+				mu.Lock()
+				active--
+				mu.Unlock()
+
+				return models.Film{ID: id}, nil
+			},
+			GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 12},
+		}
+
+		swapiMock.Use()
+		defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+		starship := models.Starship{}
+
+		for i := 1; i <= 12; i++ {
+			starship.Films = append(starship.Films, fmt.Sprintf("https://swapi.dev/api/films/%d/", i))
+		}
+
+		expansion := ExpandStarshipService(context.Background(), starship, []string{"films"})
+
+		assert.Len(t, expansion.Resources["films"], 12)
+		assert.Equal(t, models.Film{ID: 12}, expansion.Resources["films"][11])
+		assert.LessOrEqual(t, maxActive, maxConcurrentExpansions)
+	})
+}
+
+func TestExpandFields(t *testing.T) {
+	assert.Equal(t, []string{"films", "homeworld", "species", "starships"}, PeopleExpandFields())
+	assert.Equal(t, []string{"films", "pilots"}, StarshipExpandFields())
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"swapi/clients/swapi"
+)
+
+const (
+	StatusOK      = "ok"
+	StatusFailing = "failing"
+)
+
+// Check is the state of a dependency. A failing required dependency makes the
+// service not ready. Failing checks report the code of their error, its cause
+// is only logged.
+type Check struct {
+	Status   string `json:"status"`
+	Required bool   `json:"required"`
+	Code     string `json:"code,omitempty"`
+	err      error
+}
+
+type Readiness struct {
+	Status string           `json:"status"`
+	Checks map[string]Check `json:"checks"`
+}
+
+func (r Readiness) Ready() bool {
+	return r.Status == StatusOK
+}
+
+// Err returns the error of the first failing required check, by name
+func (r Readiness) Err() error {
+	for _, name := range keys(r.Checks) {
+		if c := r.Checks[name]; c.Required && c.err != nil {
+			return c.err
+		}
+	}
+
+	return nil
+}
+
+// ReadinessService checks every dependency: SWAPI, through the client stack
+func ReadinessService(ctx context.Context) Readiness {
+	readiness := Readiness{
+		Status: StatusOK,
+		Checks: map[string]Check{
+			"swapi": check(swapi.Ping(ctx, swapi.Instance), true),
+		},
+	}
+
+	for _, c := range readiness.Checks {
+		if c.Required && c.Status != StatusOK {
+			readiness.Status = StatusFailing
+		}
+	}
+
+	return readiness
+}
+
+func check(err error, required bool) Check {
+	if err != nil {
+		typed := typedError(err)
+
+		return Check{Status: StatusFailing, Required: required, Code: typed.Code, err: typed}
+	}
+
+	return Check{Status: StatusOK, Required: required}
+}
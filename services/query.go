@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"swapi/clients/swapi"
+	"swapi/errors"
+	"swapi/models"
+	"swapi/tracing"
+)
+
+// Operators of a Filter, given as a suffix of the field name such as
+// hyperdrive_rating_lt. Equality has no suffix.
+const (
+	OperatorEq  = ""
+	OperatorNe  = "ne"
+	OperatorLt  = "lt"
+	OperatorLte = "lte"
+	OperatorGt  = "gt"
+	OperatorGte = "gte"
+)
+
+var operators = []string{OperatorNe, OperatorLt, OperatorLte, OperatorGt, OperatorGte}
+
+// Filter keeps the records whose field compares to value with operator
+type Filter struct {
+	Field    string
+	Operator string
+	Value    string
+}
+
+type SortKey struct {
+	Field      string
+	Descending bool
+}
+
+// Query filters and sorts a collection. Records must match every filter and
+// are sorted by each key in turn.
+type Query struct {
+	Filters []Filter
+	Sort    []SortKey
+}
+
+func (q Query) Empty() bool {
+	return len(q.Filters) == 0 && len(q.Sort) == 0
+}
+
+// ParseFilter splits a query parameter such as hyperdrive_rating_lt into its
+// field and operator
+func ParseFilter(name string) (field string, operator string) {
+	for _, op := range operators {
+		if strings.HasSuffix(name, "_"+op) {
+			return strings.TrimSuffix(name, "_"+op), op
+		}
+	}
+
+	return name, OperatorEq
+}
+
+// queryField reads a field of a record either as text or, for numeric
+// fields, as the parsed number, nil when unknown
+type queryField[T any] struct {
+	text   func(T) string
+	number func(T) *float64
+	parse  func(string) *float64
+}
+
+func text[T any](value func(T) string) queryField[T] {
+	return queryField[T]{text: value}
+}
+
+func number[T any](value func(T) string, parse func(string) *float64) queryField[T] {
+	return queryField[T]{
+		number: func(record T) *float64 { return parse(value(record)) },
+		parse:  parse,
+	}
+}
+
+// parseValue parses a filter value the way the field is parsed, e.g. "19BBY"
+// for a birth year, also accepting a plain number
+func (f queryField[T]) parseValue(value string) *float64 {
+	if n := f.parse(value); n != nil {
+		return n
+	}
+
+	return models.ParseNumber(value)
+}
+
+// lowerBound compares ranges such as a crew of "30-165" by their minimum
+func lowerBound(value string) *float64 {
+	r := models.ParseRange(value)
+
+	if r == nil {
+		return nil
+	}
+
+	return &r.Min
+}
+
+var starshipQueryFields = map[string]queryField[models.Starship]{
+	"name":                   text(func(s models.Starship) string { return s.Name }),
+	"model":                  text(func(s models.Starship) string { return s.Model }),
+	"starship_class":         text(func(s models.Starship) string { return s.Class }),
+	"manufacturer":           text(func(s models.Starship) string { return s.Manufacturer }),
+	"cost_in_credits":        number(func(s models.Starship) string { return s.CostInCredits }, models.ParseNumber),
+	"length":                 number(func(s models.Starship) string { return s.Length }, models.ParseNumber),
+	"crew":                   number(func(s models.Starship) string { return s.Crew }, lowerBound),
+	"passengers":             number(func(s models.Starship) string { return s.Passengers }, models.ParseNumber),
+	"max_atmosphering_speed": number(func(s models.Starship) string { return s.MaxAtmospheringSpeed }, models.ParseNumber),
+	"hyperdrive_rating":      number(func(s models.Starship) string { return s.HyperdriveRating }, models.ParseNumber),
+	"MGLT":                   number(func(s models.Starship) string { return s.MGLT }, models.ParseNumber),
+	"cargo_capacity":         number(func(s models.Starship) string { return s.CargoCapacity }, models.ParseNumber),
+	"consumables":            number(func(s models.Starship) string { return s.Consumables }, models.ParseDays),
+}
+
+var peopleQueryFields = map[string]queryField[models.People]{
+	"name":       text(func(p models.People) string { return p.Name }),
+	"birth_year": number(func(p models.People) string { return p.BirthYear }, models.ParseBirthYear),
+	"eye_color":  text(func(p models.People) string { return p.EyeColor }),
+	"gender":     text(func(p models.People) string { return p.Gender }),
+	"hair_color": text(func(p models.People) string { return p.HairColor }),
+	"height":     number(func(p models.People) string { return p.Height }, models.ParseNumber),
+	"mass":       number(func(p models.People) string { return p.Mass }, models.ParseNumber),
+	"skin_color": text(func(p models.People) string { return p.SkinColor }),
+}
+
+// QueryStarshipsService filters and sorts every starship, or the matches of
+// search when given, and returns a page of the result. A pageSize of 0
+// returns every result.
+func QueryStarshipsService(ctx context.Context, search string, query Query, page int, pageSize int) (result models.Starships, err error) {
+	ctx, span := startSpan(ctx, "QueryStarshipsService", "starships", tracing.Attributes{"swapi.search": search, "swapi.page": page, "swapi.page_size": pageSize})
+	defer func() { span.End(err) }()
+
+	if err := validate(query, starshipQueryFields); err != nil {
+		return result, err
+	}
+
+	if search != "" {
+		result, err = swapi.Instance.SearchStarships(ctx, search)
+	} else {
+		result, err = swapi.Instance.GetAllStarships(ctx)
+	}
+
+	if err != nil {
+		return result, err
+	}
+
+	result.Results = apply(result.Results, query, starshipQueryFields)
+	result.Count = len(result.Results)
+
+	if pageSize == 0 {
+		return result, nil
+	}
+
+	result.Results, err = paginate(result.Results, page, pageSize, "starships")
+
+	return result, err
+}
+
+// QueryPeopleService filters and sorts every people, or the matches of search
+// when given, and returns a page of the result. A pageSize of 0 returns every
+// result.
+func QueryPeopleService(ctx context.Context, search string, query Query, page int, pageSize int) (result models.PeopleList, err error) {
+	ctx, span := startSpan(ctx, "QueryPeopleService", "people", tracing.Attributes{"swapi.search": search, "swapi.page": page, "swapi.page_size": pageSize})
+	defer func() { span.End(err) }()
+
+	if err := validate(query, peopleQueryFields); err != nil {
+		return result, err
+	}
+
+	if search != "" {
+		result, err = swapi.Instance.SearchPeople(ctx, search)
+	} else {
+		result, err = swapi.Instance.GetAllPeople(ctx)
+	}
+
+	if err != nil {
+		return result, err
+	}
+
+	result.Results = apply(result.Results, query, peopleQueryFields)
+	result.Count = len(result.Results)
+
+	if pageSize == 0 {
+		return result, nil
+	}
+
+	result.Results, err = paginate(result.Results, page, pageSize, "people")
+
+	return result, err
+}
+
+func validate[T any](query Query, fields map[string]queryField[T]) error {
+	valid := strings.Join(keys(fields), ", ")
+
+	for _, filter := range query.Filters {
+		field, ok := fields[filter.Field]
+
+		if !ok {
+			return errors.NewBadRequest(fmt.Sprintf("invalid filter %q, valid fields are: %s", filter.Field, valid))
+		}
+
+		if field.number != nil && field.parseValue(filter.Value) == nil {
+			return errors.NewBadRequest(fmt.Sprintf("invalid %s %q, it must be a number", filter.Field, filter.Value))
+		}
+	}
+
+	for _, key := range query.Sort {
+		if _, ok := fields[key.Field]; !ok {
+			return errors.NewBadRequest(fmt.Sprintf("invalid sort %q, valid fields are: %s", key.Field, valid))
+		}
+	}
+
+	return nil
+}
+
+// apply returns the records matching every filter of query, sorted by its
+// keys. Records whose numeric field is unknown never match a filter on it
+// and are sorted last whatever the direction. records is left untouched, as
+// it may be shared with the cache.
+func apply[T any](records []T, query Query, fields map[string]queryField[T]) []T {
+	result := make([]T, 0, len(records))
+
+	for _, record := range records {
+		if matches(record, query.Filters, fields) {
+			result = append(result, record)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		for _, key := range query.Sort {
+			c := compareField(result[i], result[j], fields[key.Field])
+
+			if c == 0 {
+				continue
+			}
+
+			if key.Descending && c != unknownLast && c != -unknownLast {
+				c = -c
+			}
+
+			return c < 0
+		}
+
+		return false
+	})
+
+	return result
+}
+
+func matches[T any](record T, filters []Filter, fields map[string]queryField[T]) bool {
+	for _, filter := range filters {
+		field := fields[filter.Field]
+
+		var c int
+
+		if field.number != nil {
+			value := field.number(record)
+
+			if value == nil {
+				return false
+			}
+
+			c = compareNumbers(*value, *field.parseValue(filter.Value))
+		} else {
+			c = strings.Compare(strings.ToLower(field.text(record)), strings.ToLower(filter.Value))
+		}
+
+		if !satisfies(c, filter.Operator) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func satisfies(c int, operator string) bool {
+	switch operator {
+	case OperatorNe:
+		return c != 0
+	case OperatorLt:
+		return c < 0
+	case OperatorLte:
+		return c <= 0
+	case OperatorGt:
+		return c > 0
+	case OperatorGte:
+		return c >= 0
+	default:
+		return c == 0
+	}
+}
+
+// unknownLast is returned by compareField when only one of the values is
+// unknown, so the unknown one is sorted last in both directions
+const unknownLast = 2
+
+func compareField[T any](a T, b T, field queryField[T]) int {
+	if field.number == nil {
+		return strings.Compare(strings.ToLower(field.text(a)), strings.ToLower(field.text(b)))
+	}
+
+	x, y := field.number(a), field.number(b)
+
+	switch {
+	case x == nil && y == nil:
+		return 0
+	case x == nil:
+		return unknownLast
+	case y == nil:
+		return -unknownLast
+	default:
+		return compareNumbers(*x, *y)
+	}
+}
+
+func compareNumbers(x float64, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
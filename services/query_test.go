@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"swapi/clients/swapi"
+	"swapi/errors"
+	"swapi/mockeable"
+	"swapi/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilter(t *testing.T) {
+	testCases := map[string][2]string{
+		"name":                 {"name", OperatorEq},
+		"starship_class":       {"starship_class", OperatorEq},
+		"hyperdrive_rating_lt": {"hyperdrive_rating", OperatorLt},
+		"length_lte":           {"length", OperatorLte},
+		"crew_gt":              {"crew", OperatorGt},
+		"mass_gte":             {"mass", OperatorGte},
+		"gender_ne":            {"gender", OperatorNe},
+	}
+
+	for name, expected := range testCases {
+		t.Run(name, func(t *testing.T) {
+			field, operator := ParseFilter(name)
+
+			assert.Equal(t, expected, [2]string{field, operator})
+		})
+	}
+}
+
+func TestQueryStarshipsService(t *testing.T) {
+	starships := []models.Starship{
+		{Name: "X-wing", Class: "Starfighter", Length: "12.5", HyperdriveRating: "1.0", Crew: "1"},
+		{Name: "Death Star", Class: "Deep Space Mobile Battlestation", Length: "120000", HyperdriveRating: "4.0", Crew: "342,953"},
+		{Name: "TIE Advanced x1", Class: "Starfighter", Length: "9.2", HyperdriveRating: "1.0", Crew: "1"},
+		{Name: "Y-wing", Class: "assault starfighter", Length: "14", HyperdriveRating: "1.0", Crew: "2"},
+		{Name: "Rebel transport", Class: "Medium transport", Length: "unknown", HyperdriveRating: "4.0", Crew: "6"},
+		{Name: "Millennium Falcon", Class: "Light freighter", Length: "34.37", HyperdriveRating: "0.5", Crew: "4"},
+		{Name: "Imperial shuttle", Class: "Armed government transport", Length: "20", HyperdriveRating: "unknown", Crew: "6"},
+	}
+
+	names := func(result models.Starships) []string {
+		var names []string
+
+		for _, starship := range result.Results {
+			names = append(names, starship.Name)
+		}
+
+		return names
+	}
+
+	type TestCase struct {
+		Name               string
+		Query              Query
+		Page               int
+		PageSize           int
+		ExpectedNames      []string
+		ExpectedCount      int
+		ExpectedError      string
+		ExpectedMockCalls  int
+		ExpectedStatusCode int
+	}
+
+	testCases := []TestCase{
+		{
+			Name:              "Equality ignores case",
+			Query:             Query{Filters: []Filter{{Field: "starship_class", Value: "starfighter"}}},
+			ExpectedNames:     []string{"X-wing", "TIE Advanced x1"},
+			ExpectedCount:     2,
+			ExpectedMockCalls: 1,
+		},
+		{
+			Name: "Numeric comparison and descending sort",
+			Query: Query{
+				Filters: []Filter{{Field: "starship_class", Value: "Starfighter"}, {Field: "hyperdrive_rating", Operator: OperatorLt, Value: "2"}},
+				Sort:    []SortKey{{Field: "length", Descending: true}},
+			},
+			ExpectedNames:     []string{"X-wing", "TIE Advanced x1"},
+			ExpectedCount:     2,
+			ExpectedMockCalls: 1,
+		},
+		{
+			Name:              "Unknown values never match",
+			Query:             Query{Filters: []Filter{{Field: "hyperdrive_rating", Operator: OperatorNe, Value: "1"}}},
+			ExpectedNames:     []string{"Death Star", "Rebel transport", "Millennium Falcon"},
+			ExpectedCount:     3,
+			ExpectedMockCalls: 1,
+		},
+		{
+			Name:              "Unknown values sort last ascending",
+			Query:             Query{Filters: []Filter{{Field: "crew", Operator: OperatorGte, Value: "4"}}, Sort: []SortKey{{Field: "length"}}},
+			ExpectedNames:     []string{"Imperial shuttle", "Millennium Falcon", "Death Star", "Rebel transport"},
+			ExpectedCount:     4,
+			ExpectedMockCalls: 1,
+		},
+		{
+			Name:              "Unknown values sort last descending",
+			Query:             Query{Filters: []Filter{{Field: "crew", Operator: OperatorGte, Value: "4"}}, Sort: []SortKey{{Field: "length", Descending: true}}},
+			ExpectedNames:     []string{"Death Star", "Millennium Falcon", "Imperial shuttle", "Rebel transport"},
+			ExpectedCount:     4,
+			ExpectedMockCalls: 1,
+		},
+		{
+			Name:              "Multiple keys",
+			Query:             Query{Sort: []SortKey{{Field: "hyperdrive_rating"}, {Field: "name", Descending: true}}},
+			ExpectedNames:     []string{"Millennium Falcon", "Y-wing", "X-wing", "TIE Advanced x1", "Rebel transport", "Death Star", "Imperial shuttle"},
+			ExpectedCount:     7,
+			ExpectedMockCalls: 1,
+		},
+		{
+			Name:              "Stable",
+			Query:             Query{Sort: []SortKey{{Field: "hyperdrive_rating"}}},
+			ExpectedNames:     []string{"Millennium Falcon", "X-wing", "TIE Advanced x1", "Y-wing", "Death Star", "Rebel transport", "Imperial shuttle"},
+			ExpectedCount:     7,
+			ExpectedMockCalls: 1,
+		},
+		{
+			Name:              "Paginated",
+			Query:             Query{Sort: []SortKey{{Field: "name"}}},
+			Page:              2,
+			PageSize:          3,
+			ExpectedNames:     []string{"Rebel transport", "TIE Advanced x1", "X-wing"},
+			ExpectedCount:     7,
+			ExpectedMockCalls: 1,
+		},
+		{
+			Name:               "Invalid number",
+			Query:              Query{Filters: []Filter{{Field: "length", Operator: OperatorGt, Value: "long"}}},
+			ExpectedError:      `Bad request. Reason: invalid length "long", it must be a number`,
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name:               "Invalid sort",
+			Query:              Query{Sort: []SortKey{{Field: "speed"}}},
+			ExpectedError:      `Bad request. Reason: invalid sort "speed", valid fields are: MGLT, cargo_capacity, consumables, cost_in_credits, crew, hyperdrive_rating, length, manufacturer, max_atmosphering_speed, model, name, passengers, starship_class`,
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			cached := append([]models.Starship{}, starships...)
+
+			swapiMock := swapi.MockClient{
+				GetAllStarshipsFunc: func(ctx context.Context) (models.Starships, error) {
+					return models.Starships{Count: len(cached), Results: cached}, nil
+				},
+				GetAllStarshipsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedMockCalls},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			result, err := QueryStarshipsService(context.Background(), "", tc.Query, tc.Page, tc.PageSize)
+
+			if tc.ExpectedError != "" {
+				assert.EqualError(t, err, tc.ExpectedError)
+				assert.Equal(t, tc.ExpectedStatusCode, errors.Status(err))
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.ExpectedNames, names(result))
+			assert.Equal(t, tc.ExpectedCount, result.Count)
+			// the collection may be shared with the cache
+			assert.Equal(t, starships, cached)
+		})
+	}
+}
+
+func TestQueryPeopleService(t *testing.T) {
+	people := []models.People{
+		{Name: "Luke Skywalker", Gender: "male", BirthYear: "19BBY", Height: "172"},
+		{Name: "Leia Organa", Gender: "female", BirthYear: "19BBY", Height: "150"},
+		{Name: "Padmé Amidala", Gender: "female", BirthYear: "46BBY", Height: "185"},
+		{Name: "Beru Whitesun lars", Gender: "female", BirthYear: "47BBY", Height: "165"},
+		{Name: "Rey", Gender: "female", BirthYear: "unknown", Height: "unknown"},
+	}
+
+	swapiMock := swapi.MockClient{
+		SearchPeopleFunc: func(ctx context.Context, query string) (models.PeopleList, error) {
+			assert.Equal(t, "a", query)
+			return models.PeopleList{Count: len(people), Results: people}, nil
+		},
+		SearchPeopleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	swapiMock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+	query := Query{
+		Filters: []Filter{{Field: "gender", Value: "female"}, {Field: "birth_year", Operator: OperatorGt, Value: "20BBY"}},
+		Sort:    []SortKey{{Field: "name"}},
+	}
+
+	result, err := QueryPeopleService(context.Background(), "a", query, 1, 0)
+
+	assert.Nil(t, err)
+	assert.Equal(t, models.PeopleList{Count: 2, Results: []models.People{people[3], people[2]}}, result)
+}
@@ -1,22 +1,293 @@
 package services
 
 import (
+	"context"
+	stderrors "errors"
 	"swapi/clients/swapi"
+	"swapi/errors"
 	"swapi/models"
+	"swapi/tracing"
 )
 
-func GetStarshipService(id int) (models.Starship, error) {
-	return swapi.Instance.GetStarship(id)
+func GetStarshipService(ctx context.Context, id int) (result models.Starship, err error) {
+	ctx, span := startSpan(ctx, "GetStarshipService", "starships", tracing.Attributes{"swapi.id": id})
+	defer func() { span.End(err) }()
+
+	return swapi.Instance.GetStarship(ctx, id)
 }
 
-func GetStarshipsService() (models.Starships, error) {
-	return swapi.Instance.GetStarships()
+func GetStarshipsService(ctx context.Context, page int, pageSize int) (result models.Starships, err error) {
+	ctx, span := startSpan(ctx, "GetStarshipsService", "starships", tracing.Attributes{"swapi.page": page, "swapi.page_size": pageSize})
+	defer func() { span.End(err) }()
+
+	if pageSize == swapi.PageSize {
+		return swapi.Instance.GetStarships(ctx, page)
+	}
+
+	result, err = swapi.Instance.GetAllStarships(ctx)
+
+	if err != nil {
+		return result, err
+	}
+
+	result.Results, err = paginate(result.Results, page, pageSize, "starships")
+
+	return result, err
 }
 
-func GetPeopleService(id int) (models.People, error) {
-	return swapi.Instance.GetPeople(id)
+func GetAllStarshipsService(ctx context.Context) (result models.Starships, err error) {
+	ctx, span := startSpan(ctx, "GetAllStarshipsService", "starships", nil)
+	defer func() { span.End(err) }()
+
+	return swapi.Instance.GetAllStarships(ctx)
+}
+
+// SearchStarshipsService returns the matches of query, a page at a time. A pageSize
+// of 0 returns every match.
+func SearchStarshipsService(ctx context.Context, query string, page int, pageSize int) (result models.Starships, err error) {
+	ctx, span := startSpan(ctx, "SearchStarshipsService", "starships", tracing.Attributes{"swapi.search": query, "swapi.page": page, "swapi.page_size": pageSize})
+	defer func() { span.End(err) }()
+
+	result, err = swapi.Instance.SearchStarships(ctx, query)
+
+	if err != nil || pageSize == 0 {
+		return result, err
+	}
+
+	result.Results, err = paginate(result.Results, page, pageSize, "starships")
+
+	return result, err
 }
 
-func GetPeopleListService() (models.PeopleList, error) {
-	return swapi.Instance.GetPeopleList()
+func GetPeopleService(ctx context.Context, id int) (result models.People, err error) {
+	ctx, span := startSpan(ctx, "GetPeopleService", "people", tracing.Attributes{"swapi.id": id})
+	defer func() { span.End(err) }()
+
+	return swapi.Instance.GetPeople(ctx, id)
+}
+
+func GetPeopleListService(ctx context.Context, page int, pageSize int) (result models.PeopleList, err error) {
+	ctx, span := startSpan(ctx, "GetPeopleListService", "people", tracing.Attributes{"swapi.page": page, "swapi.page_size": pageSize})
+	defer func() { span.End(err) }()
+
+	if pageSize == swapi.PageSize {
+		return swapi.Instance.GetPeopleList(ctx, page)
+	}
+
+	result, err = swapi.Instance.GetAllPeople(ctx)
+
+	if err != nil {
+		return result, err
+	}
+
+	result.Results, err = paginate(result.Results, page, pageSize, "people")
+
+	return result, err
+}
+
+func GetAllPeopleService(ctx context.Context) (result models.PeopleList, err error) {
+	ctx, span := startSpan(ctx, "GetAllPeopleService", "people", nil)
+	defer func() { span.End(err) }()
+
+	return swapi.Instance.GetAllPeople(ctx)
+}
+
+// SearchPeopleService returns the matches of query, a page at a time. A pageSize
+// of 0 returns every match.
+func SearchPeopleService(ctx context.Context, query string, page int, pageSize int) (result models.PeopleList, err error) {
+	ctx, span := startSpan(ctx, "SearchPeopleService", "people", tracing.Attributes{"swapi.search": query, "swapi.page": page, "swapi.page_size": pageSize})
+	defer func() { span.End(err) }()
+
+	result, err = swapi.Instance.SearchPeople(ctx, query)
+
+	if err != nil || pageSize == 0 {
+		return result, err
+	}
+
+	result.Results, err = paginate(result.Results, page, pageSize, "people")
+
+	return result, err
+}
+
+func GetFilmService(ctx context.Context, id int) (result models.Film, err error) {
+	ctx, span := startSpan(ctx, "GetFilmService", "films", tracing.Attributes{"swapi.id": id})
+	defer func() { span.End(err) }()
+
+	return swapi.Instance.GetFilm(ctx, id)
+}
+
+func GetFilmsService(ctx context.Context, page int, pageSize int) (result models.Films, err error) {
+	ctx, span := startSpan(ctx, "GetFilmsService", "films", tracing.Attributes{"swapi.page": page, "swapi.page_size": pageSize})
+	defer func() { span.End(err) }()
+
+	if pageSize == swapi.PageSize {
+		return swapi.Instance.GetFilms(ctx, page)
+	}
+
+	result, err = swapi.Instance.GetAllFilms(ctx)
+
+	if err != nil {
+		return result, err
+	}
+
+	result.Results, err = paginate(result.Results, page, pageSize, "films")
+
+	return result, err
+}
+
+func GetAllFilmsService(ctx context.Context) (result models.Films, err error) {
+	ctx, span := startSpan(ctx, "GetAllFilmsService", "films", nil)
+	defer func() { span.End(err) }()
+
+	return swapi.Instance.GetAllFilms(ctx)
+}
+
+func GetPlanetService(ctx context.Context, id int) (result models.Planet, err error) {
+	ctx, span := startSpan(ctx, "GetPlanetService", "planets", tracing.Attributes{"swapi.id": id})
+	defer func() { span.End(err) }()
+
+	return swapi.Instance.GetPlanet(ctx, id)
+}
+
+func GetPlanetsService(ctx context.Context, page int, pageSize int) (result models.Planets, err error) {
+	ctx, span := startSpan(ctx, "GetPlanetsService", "planets", tracing.Attributes{"swapi.page": page, "swapi.page_size": pageSize})
+	defer func() { span.End(err) }()
+
+	if pageSize == swapi.PageSize {
+		return swapi.Instance.GetPlanets(ctx, page)
+	}
+
+	result, err = swapi.Instance.GetAllPlanets(ctx)
+
+	if err != nil {
+		return result, err
+	}
+
+	result.Results, err = paginate(result.Results, page, pageSize, "planets")
+
+	return result, err
+}
+
+func GetAllPlanetsService(ctx context.Context) (result models.Planets, err error) {
+	ctx, span := startSpan(ctx, "GetAllPlanetsService", "planets", nil)
+	defer func() { span.End(err) }()
+
+	return swapi.Instance.GetAllPlanets(ctx)
+}
+
+func GetSpeciesService(ctx context.Context, id int) (result models.Species, err error) {
+	ctx, span := startSpan(ctx, "GetSpeciesService", "species", tracing.Attributes{"swapi.id": id})
+	defer func() { span.End(err) }()
+
+	return swapi.Instance.GetSpecies(ctx, id)
+}
+
+func GetSpeciesListService(ctx context.Context, page int, pageSize int) (result models.SpeciesList, err error) {
+	ctx, span := startSpan(ctx, "GetSpeciesListService", "species", tracing.Attributes{"swapi.page": page, "swapi.page_size": pageSize})
+	defer func() { span.End(err) }()
+
+	if pageSize == swapi.PageSize {
+		return swapi.Instance.GetSpeciesList(ctx, page)
+	}
+
+	result, err = swapi.Instance.GetAllSpecies(ctx)
+
+	if err != nil {
+		return result, err
+	}
+
+	result.Results, err = paginate(result.Results, page, pageSize, "species")
+
+	return result, err
+}
+
+func GetAllSpeciesService(ctx context.Context) (result models.SpeciesList, err error) {
+	ctx, span := startSpan(ctx, "GetAllSpeciesService", "species", nil)
+	defer func() { span.End(err) }()
+
+	return swapi.Instance.GetAllSpecies(ctx)
+}
+
+func GetVehicleService(ctx context.Context, id int) (result models.Vehicle, err error) {
+	ctx, span := startSpan(ctx, "GetVehicleService", "vehicles", tracing.Attributes{"swapi.id": id})
+	defer func() { span.End(err) }()
+
+	return swapi.Instance.GetVehicle(ctx, id)
+}
+
+func GetVehiclesService(ctx context.Context, page int, pageSize int) (result models.Vehicles, err error) {
+	ctx, span := startSpan(ctx, "GetVehiclesService", "vehicles", tracing.Attributes{"swapi.page": page, "swapi.page_size": pageSize})
+	defer func() { span.End(err) }()
+
+	if pageSize == swapi.PageSize {
+		return swapi.Instance.GetVehicles(ctx, page)
+	}
+
+	result, err = swapi.Instance.GetAllVehicles(ctx)
+
+	if err != nil {
+		return result, err
+	}
+
+	result.Results, err = paginate(result.Results, page, pageSize, "vehicles")
+
+	return result, err
+}
+
+func GetAllVehiclesService(ctx context.Context) (result models.Vehicles, err error) {
+	ctx, span := startSpan(ctx, "GetAllVehiclesService", "vehicles", nil)
+	defer func() { span.End(err) }()
+
+	return swapi.Instance.GetAllVehicles(ctx)
+}
+
+func GetDiagnosticsService() swapi.Diagnostics {
+	return swapi.Diagnose(swapi.Instance)
+}
+
+// paginate slices an aggregated collection when the requested page size
+// differs from the upstream one. Out of range pages are reported as not found,
+// the same way SWAPI does.
+func paginate[T any](results []T, page int, pageSize int, resource string) ([]T, error) {
+	start := (page - 1) * pageSize
+
+	if start >= len(results) {
+		if page == 1 {
+			return results, nil
+		}
+
+		return nil, errors.NewNotFound(resource, "")
+	}
+
+	end := start + pageSize
+
+	if end > len(results) {
+		end = len(results)
+	}
+
+	return results[start:end], nil
+}
+
+// startSpan starts the span of a service call on resource
+func startSpan(ctx context.Context, name string, resource string, attributes tracing.Attributes) (context.Context, *tracing.Span) {
+	ctx, span := tracing.Start(ctx, tracing.KindInternal, "services."+name, attributes)
+	span.SetAttributes(tracing.Attributes{"swapi.resource": resource})
+
+	return ctx, span
+}
+
+// typedError returns err as an *errors.Error, so that it's reported with a
+// type, a code and a message. Untyped errors are internal ones, but for
+// deadlines which are gateway timeouts.
+func typedError(err error) *errors.Error {
+	var typed *errors.Error
+
+	switch {
+	case stderrors.As(err, &typed):
+		return typed
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return errors.NewGatewayTimeout().WithCause(err)
+	default:
+		return errors.NewInternal().WithCause(err)
+	}
 }
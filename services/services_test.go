@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"net/http"
 	"swapi/clients/swapi"
 	"swapi/errors"
@@ -72,7 +73,7 @@ func TestGetStarshipService(t *testing.T) {
 		t.Run(tc.Name, func(t *testing.T) {
 			// Create mock client
 			swapiMock := swapi.MockClient{
-				GetStarshipFunc: func(id int) (models.Starship, error) {
+				GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
 					assert.Equal(t, tc.IDToCall, id)
 					return tc.ExpectedSuccessResponse, tc.ExpectedErrorResponse
 				},
@@ -82,7 +83,7 @@ func TestGetStarshipService(t *testing.T) {
 			swapiMock.Use()
 			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
 
-			result, err := GetStarshipService(tc.IDToCall)
+			result, err := GetStarshipService(context.Background(), tc.IDToCall)
 
 			if tc.IsErrorFlow {
 				assert.NotNil(t, err)
@@ -159,7 +160,8 @@ func TestGetStarshipsService(t *testing.T) {
 
 			// Create mock client
 			swapiMock := swapi.MockClient{
-				GetStarshipsFunc: func() (models.Starships, error) {
+				GetStarshipsFunc: func(ctx context.Context, page int) (models.Starships, error) {
+					assert.Equal(t, 1, page)
 					return tc.ExpectedSuccessResponse, tc.ExpectedErrorResponse
 				},
 				GetStarshipsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedCallCount},
@@ -168,7 +170,7 @@ func TestGetStarshipsService(t *testing.T) {
 			swapiMock.Use()
 			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
 
-			result, err := GetStarshipsService()
+			result, err := GetStarshipsService(context.Background(), 1, swapi.PageSize)
 
 			if tc.IsErrorFlow {
 				assert.NotNil(t, err)
@@ -234,7 +236,7 @@ func TestGetPeople(t *testing.T) {
 
 			// Create mock client
 			swapiMock := swapi.MockClient{
-				GetPeopleFunc: func(id int) (models.People, error) {
+				GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
 					assert.Equal(t, tc.IDToCall, id)
 					return tc.ExpectedSuccessResponse, tc.ExpectedErrorResponse
 				},
@@ -244,7 +246,7 @@ func TestGetPeople(t *testing.T) {
 			swapiMock.Use()
 			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
 
-			result, err := GetPeopleService(tc.IDToCall)
+			result, err := GetPeopleService(context.Background(), tc.IDToCall)
 
 			if tc.IsErrorFlow {
 				assert.NotNil(t, err)
@@ -310,7 +312,8 @@ func TestGetPeopleList(t *testing.T) {
 
 			// Create mock client
 			swapiMock := swapi.MockClient{
-				GetPeopleListFunc: func() (models.PeopleList, error) {
+				GetPeopleListFunc: func(ctx context.Context, page int) (models.PeopleList, error) {
+					assert.Equal(t, 1, page)
 					return tc.ExpectedSuccessResponse, tc.ExpectedErrorResponse
 				},
 				GetPeopleListFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedCallCount},
@@ -319,7 +322,7 @@ func TestGetPeopleList(t *testing.T) {
 			swapiMock.Use()
 			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
 
-			result, err := GetPeopleListService()
+			result, err := GetPeopleListService(context.Background(), 1, swapi.PageSize)
 
 			if tc.IsErrorFlow {
 				assert.NotNil(t, err)
@@ -334,3 +337,875 @@ func TestGetPeopleList(t *testing.T) {
 		})
 	}
 }
+
+func TestGetFilmService(t *testing.T) {
+
+	type TestCase struct {
+		Name                    string
+		IsErrorFlow             bool
+		IDToCall                int
+		ExpectedSuccessResponse models.Film
+		ExpectedErrorResponse   error
+		ExpectedCallCount       int
+		ExpectedStatusCode      int
+	}
+
+	testCases := []TestCase{
+		{
+			Name: "Success",
+			ExpectedSuccessResponse: models.Film{
+				Title:       "A New Hope",
+				EpisodeID:   4,
+				Director:    "George Lucas",
+				Producer:    "Gary Kurtz, Rick McCallum",
+				ReleaseDate: "1977-05-25",
+				Characters: []string{
+					"https://swapi.dev/api/people/1/",
+				},
+			},
+			ExpectedCallCount: 1,
+			IDToCall:          1,
+		},
+		{
+			Name:                  "Not Found",
+			IsErrorFlow:           true,
+			ExpectedErrorResponse: errors.NewNotFound("films", "1"),
+			ExpectedCallCount:     1,
+			ExpectedStatusCode:    http.StatusNotFound,
+			IDToCall:              1,
+		},
+		{
+			Name:                  "Internal Server Error",
+			IsErrorFlow:           true,
+			ExpectedErrorResponse: errors.NewInternal(),
+			ExpectedCallCount:     1,
+			ExpectedStatusCode:    http.StatusInternalServerError,
+			IDToCall:              1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create mock client
+			swapiMock := swapi.MockClient{
+				GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+					assert.Equal(t, tc.IDToCall, id)
+					return tc.ExpectedSuccessResponse, tc.ExpectedErrorResponse
+				},
+				GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedCallCount},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			result, err := GetFilmService(context.Background(), tc.IDToCall)
+
+			if tc.IsErrorFlow {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.ExpectedErrorResponse, err)
+				assert.Equal(t, tc.ExpectedStatusCode, errors.Status(err))
+			} else {
+				assert.Nil(t, err)
+				assert.NotNil(t, result)
+				assert.Equal(t, tc.ExpectedSuccessResponse, result)
+			}
+		})
+	}
+}
+
+func TestGetFilmsService(t *testing.T) {
+
+	type TestCase struct {
+		Name                    string
+		IsErrorFlow             bool
+		ExpectedSuccessResponse models.Films
+		ExpectedErrorResponse   error
+		ExpectedCallCount       int
+		ExpectedStatusCode      int
+	}
+
+	testCases := []TestCase{
+		{
+			Name: "Success",
+			ExpectedSuccessResponse: models.Films{
+				Count: 1,
+				Results: []models.Film{
+					{
+						Title:       "A New Hope",
+						EpisodeID:   4,
+						Director:    "George Lucas",
+						Producer:    "Gary Kurtz, Rick McCallum",
+						ReleaseDate: "1977-05-25",
+						Characters: []string{
+							"https://swapi.dev/api/people/1/",
+						},
+					},
+				},
+			},
+			ExpectedCallCount: 1,
+		},
+		{
+			Name:                  "Not Found",
+			IsErrorFlow:           true,
+			ExpectedErrorResponse: errors.NewNotFound("films", ""),
+			ExpectedCallCount:     1,
+			ExpectedStatusCode:    http.StatusNotFound,
+		},
+		{
+			Name:                  "Internal Server Error",
+			IsErrorFlow:           true,
+			ExpectedErrorResponse: errors.NewInternal(),
+			ExpectedCallCount:     1,
+			ExpectedStatusCode:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create mock client
+			swapiMock := swapi.MockClient{
+				GetFilmsFunc: func(ctx context.Context, page int) (models.Films, error) {
+					assert.Equal(t, 1, page)
+					return tc.ExpectedSuccessResponse, tc.ExpectedErrorResponse
+				},
+				GetFilmsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedCallCount},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			result, err := GetFilmsService(context.Background(), 1, swapi.PageSize)
+
+			if tc.IsErrorFlow {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.ExpectedErrorResponse, err)
+				assert.Equal(t, tc.ExpectedStatusCode, errors.Status(err))
+			} else {
+				assert.Nil(t, err)
+				assert.NotNil(t, result)
+				assert.Equal(t, tc.ExpectedSuccessResponse, result)
+			}
+		})
+	}
+}
+
+func TestGetPlanetService(t *testing.T) {
+
+	type TestCase struct {
+		Name                    string
+		IsErrorFlow             bool
+		IDToCall                int
+		ExpectedSuccessResponse models.Planet
+		ExpectedErrorResponse   error
+		ExpectedCallCount       int
+		ExpectedStatusCode      int
+	}
+
+	testCases := []TestCase{
+		{
+			Name: "Success",
+			ExpectedSuccessResponse: models.Planet{
+				Name:           "Tatooine",
+				RotationPeriod: "23",
+				OrbitalPeriod:  "304",
+				Diameter:       "10465",
+				Climate:        "arid",
+				Gravity:        "1 standard",
+				Terrain:        "desert",
+				SurfaceWater:   "1",
+				Population:     "200000",
+				Residents: []string{
+					"https://swapi.dev/api/people/1/",
+				},
+			},
+			ExpectedCallCount: 1,
+			IDToCall:          1,
+		},
+		{
+			Name:                  "Not Found",
+			IsErrorFlow:           true,
+			ExpectedErrorResponse: errors.NewNotFound("planets", "1"),
+			ExpectedCallCount:     1,
+			ExpectedStatusCode:    http.StatusNotFound,
+			IDToCall:              1,
+		},
+		{
+			Name:                  "Internal Server Error",
+			IsErrorFlow:           true,
+			ExpectedErrorResponse: errors.NewInternal(),
+			ExpectedCallCount:     1,
+			ExpectedStatusCode:    http.StatusInternalServerError,
+			IDToCall:              1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create mock client
+			swapiMock := swapi.MockClient{
+				GetPlanetFunc: func(ctx context.Context, id int) (models.Planet, error) {
+					assert.Equal(t, tc.IDToCall, id)
+					return tc.ExpectedSuccessResponse, tc.ExpectedErrorResponse
+				},
+				GetPlanetFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedCallCount},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			result, err := GetPlanetService(context.Background(), tc.IDToCall)
+
+			if tc.IsErrorFlow {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.ExpectedErrorResponse, err)
+				assert.Equal(t, tc.ExpectedStatusCode, errors.Status(err))
+			} else {
+				assert.Nil(t, err)
+				assert.NotNil(t, result)
+				assert.Equal(t, tc.ExpectedSuccessResponse, result)
+			}
+		})
+	}
+}
+
+func TestGetPlanetsService(t *testing.T) {
+
+	type TestCase struct {
+		Name                    string
+		IsErrorFlow             bool
+		ExpectedSuccessResponse models.Planets
+		ExpectedErrorResponse   error
+		ExpectedCallCount       int
+		ExpectedStatusCode      int
+	}
+
+	testCases := []TestCase{
+		{
+			Name: "Success",
+			ExpectedSuccessResponse: models.Planets{
+				Count: 1,
+				Results: []models.Planet{
+					{
+						Name:           "Tatooine",
+						RotationPeriod: "23",
+						OrbitalPeriod:  "304",
+						Diameter:       "10465",
+						Climate:        "arid",
+						Gravity:        "1 standard",
+						Terrain:        "desert",
+						SurfaceWater:   "1",
+						Population:     "200000",
+						Residents: []string{
+							"https://swapi.dev/api/people/1/",
+						},
+					},
+				},
+			},
+			ExpectedCallCount: 1,
+		},
+		{
+			Name:                  "Not Found",
+			IsErrorFlow:           true,
+			ExpectedErrorResponse: errors.NewNotFound("planets", ""),
+			ExpectedCallCount:     1,
+			ExpectedStatusCode:    http.StatusNotFound,
+		},
+		{
+			Name:                  "Internal Server Error",
+			IsErrorFlow:           true,
+			ExpectedErrorResponse: errors.NewInternal(),
+			ExpectedCallCount:     1,
+			ExpectedStatusCode:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create mock client
+			swapiMock := swapi.MockClient{
+				GetPlanetsFunc: func(ctx context.Context, page int) (models.Planets, error) {
+					assert.Equal(t, 1, page)
+					return tc.ExpectedSuccessResponse, tc.ExpectedErrorResponse
+				},
+				GetPlanetsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedCallCount},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			result, err := GetPlanetsService(context.Background(), 1, swapi.PageSize)
+
+			if tc.IsErrorFlow {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.ExpectedErrorResponse, err)
+				assert.Equal(t, tc.ExpectedStatusCode, errors.Status(err))
+			} else {
+				assert.Nil(t, err)
+				assert.NotNil(t, result)
+				assert.Equal(t, tc.ExpectedSuccessResponse, result)
+			}
+		})
+	}
+}
+
+func TestGetSpeciesService(t *testing.T) {
+
+	type TestCase struct {
+		Name                    string
+		IsErrorFlow             bool
+		IDToCall                int
+		ExpectedSuccessResponse models.Species
+		ExpectedErrorResponse   error
+		ExpectedCallCount       int
+		ExpectedStatusCode      int
+	}
+
+	testCases := []TestCase{
+		{
+			Name: "Success",
+			ExpectedSuccessResponse: models.Species{
+				Name:            "Wookie",
+				Classification:  "mammal",
+				Designation:     "sentient",
+				AverageHeight:   "210",
+				AverageLifespan: "400",
+				EyeColors:       "blue, green, yellow, brown, golden, red",
+				HairColors:      "black, brown",
+				SkinColors:      "gray",
+				Language:        "Shyriiwook",
+				Homeworld:       "https://swapi.dev/api/planets/14/",
+			},
+			ExpectedCallCount: 1,
+			IDToCall:          1,
+		},
+		{
+			Name:                  "Not Found",
+			IsErrorFlow:           true,
+			ExpectedErrorResponse: errors.NewNotFound("species", "1"),
+			ExpectedCallCount:     1,
+			ExpectedStatusCode:    http.StatusNotFound,
+			IDToCall:              1,
+		},
+		{
+			Name:                  "Internal Server Error",
+			IsErrorFlow:           true,
+			ExpectedErrorResponse: errors.NewInternal(),
+			ExpectedCallCount:     1,
+			ExpectedStatusCode:    http.StatusInternalServerError,
+			IDToCall:              1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create mock client
+			swapiMock := swapi.MockClient{
+				GetSpeciesFunc: func(ctx context.Context, id int) (models.Species, error) {
+					assert.Equal(t, tc.IDToCall, id)
+					return tc.ExpectedSuccessResponse, tc.ExpectedErrorResponse
+				},
+				GetSpeciesFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedCallCount},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			result, err := GetSpeciesService(context.Background(), tc.IDToCall)
+
+			if tc.IsErrorFlow {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.ExpectedErrorResponse, err)
+				assert.Equal(t, tc.ExpectedStatusCode, errors.Status(err))
+			} else {
+				assert.Nil(t, err)
+				assert.NotNil(t, result)
+				assert.Equal(t, tc.ExpectedSuccessResponse, result)
+			}
+		})
+	}
+}
+
+func TestGetSpeciesListService(t *testing.T) {
+
+	type TestCase struct {
+		Name                    string
+		IsErrorFlow             bool
+		ExpectedSuccessResponse models.SpeciesList
+		ExpectedErrorResponse   error
+		ExpectedCallCount       int
+		ExpectedStatusCode      int
+	}
+
+	testCases := []TestCase{
+		{
+			Name: "Success",
+			ExpectedSuccessResponse: models.SpeciesList{
+				Count: 1,
+				Results: []models.Species{
+					{
+						Name:            "Wookie",
+						Classification:  "mammal",
+						Designation:     "sentient",
+						AverageHeight:   "210",
+						AverageLifespan: "400",
+						EyeColors:       "blue, green, yellow, brown, golden, red",
+						HairColors:      "black, brown",
+						SkinColors:      "gray",
+						Language:        "Shyriiwook",
+						Homeworld:       "https://swapi.dev/api/planets/14/",
+					},
+				},
+			},
+			ExpectedCallCount: 1,
+		},
+		{
+			Name:                  "Not Found",
+			IsErrorFlow:           true,
+			ExpectedErrorResponse: errors.NewNotFound("species", ""),
+			ExpectedCallCount:     1,
+			ExpectedStatusCode:    http.StatusNotFound,
+		},
+		{
+			Name:                  "Internal Server Error",
+			IsErrorFlow:           true,
+			ExpectedErrorResponse: errors.NewInternal(),
+			ExpectedCallCount:     1,
+			ExpectedStatusCode:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create mock client
+			swapiMock := swapi.MockClient{
+				GetSpeciesListFunc: func(ctx context.Context, page int) (models.SpeciesList, error) {
+					assert.Equal(t, 1, page)
+					return tc.ExpectedSuccessResponse, tc.ExpectedErrorResponse
+				},
+				GetSpeciesListFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedCallCount},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			result, err := GetSpeciesListService(context.Background(), 1, swapi.PageSize)
+
+			if tc.IsErrorFlow {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.ExpectedErrorResponse, err)
+				assert.Equal(t, tc.ExpectedStatusCode, errors.Status(err))
+			} else {
+				assert.Nil(t, err)
+				assert.NotNil(t, result)
+				assert.Equal(t, tc.ExpectedSuccessResponse, result)
+			}
+		})
+	}
+}
+
+func TestGetVehicleService(t *testing.T) {
+
+	type TestCase struct {
+		Name                    string
+		IsErrorFlow             bool
+		IDToCall                int
+		ExpectedSuccessResponse models.Vehicle
+		ExpectedErrorResponse   error
+		ExpectedCallCount       int
+		ExpectedStatusCode      int
+	}
+
+	testCases := []TestCase{
+		{
+			Name: "Success",
+			ExpectedSuccessResponse: models.Vehicle{
+				Name:                 "Sand Crawler",
+				Model:                "Digger Crawler",
+				Class:                "wheeled",
+				Manufacturer:         "Corellia Mining Corporation",
+				CostInCredits:        "150000",
+				Length:               "36.8",
+				Crew:                 "46",
+				Passengers:           "30",
+				MaxAtmospheringSpeed: "30",
+				CargoCapacity:        "50000",
+				Consumables:          "2 months",
+			},
+			ExpectedCallCount: 1,
+			IDToCall:          1,
+		},
+		{
+			Name:                  "Not Found",
+			IsErrorFlow:           true,
+			ExpectedErrorResponse: errors.NewNotFound("vehicles", "1"),
+			ExpectedCallCount:     1,
+			ExpectedStatusCode:    http.StatusNotFound,
+			IDToCall:              1,
+		},
+		{
+			Name:                  "Internal Server Error",
+			IsErrorFlow:           true,
+			ExpectedErrorResponse: errors.NewInternal(),
+			ExpectedCallCount:     1,
+			ExpectedStatusCode:    http.StatusInternalServerError,
+			IDToCall:              1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create mock client
+			swapiMock := swapi.MockClient{
+				GetVehicleFunc: func(ctx context.Context, id int) (models.Vehicle, error) {
+					assert.Equal(t, tc.IDToCall, id)
+					return tc.ExpectedSuccessResponse, tc.ExpectedErrorResponse
+				},
+				GetVehicleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedCallCount},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			result, err := GetVehicleService(context.Background(), tc.IDToCall)
+
+			if tc.IsErrorFlow {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.ExpectedErrorResponse, err)
+				assert.Equal(t, tc.ExpectedStatusCode, errors.Status(err))
+			} else {
+				assert.Nil(t, err)
+				assert.NotNil(t, result)
+				assert.Equal(t, tc.ExpectedSuccessResponse, result)
+			}
+		})
+	}
+}
+
+func TestGetVehiclesService(t *testing.T) {
+
+	type TestCase struct {
+		Name                    string
+		IsErrorFlow             bool
+		ExpectedSuccessResponse models.Vehicles
+		ExpectedErrorResponse   error
+		ExpectedCallCount       int
+		ExpectedStatusCode      int
+	}
+
+	testCases := []TestCase{
+		{
+			Name: "Success",
+			ExpectedSuccessResponse: models.Vehicles{
+				Count: 1,
+				Results: []models.Vehicle{
+					{
+						Name:                 "Sand Crawler",
+						Model:                "Digger Crawler",
+						Class:                "wheeled",
+						Manufacturer:         "Corellia Mining Corporation",
+						CostInCredits:        "150000",
+						Length:               "36.8",
+						Crew:                 "46",
+						Passengers:           "30",
+						MaxAtmospheringSpeed: "30",
+						CargoCapacity:        "50000",
+						Consumables:          "2 months",
+					},
+				},
+			},
+			ExpectedCallCount: 1,
+		},
+		{
+			Name:                  "Not Found",
+			IsErrorFlow:           true,
+			ExpectedErrorResponse: errors.NewNotFound("vehicles", ""),
+			ExpectedCallCount:     1,
+			ExpectedStatusCode:    http.StatusNotFound,
+		},
+		{
+			Name:                  "Internal Server Error",
+			IsErrorFlow:           true,
+			ExpectedErrorResponse: errors.NewInternal(),
+			ExpectedCallCount:     1,
+			ExpectedStatusCode:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create mock client
+			swapiMock := swapi.MockClient{
+				GetVehiclesFunc: func(ctx context.Context, page int) (models.Vehicles, error) {
+					assert.Equal(t, 1, page)
+					return tc.ExpectedSuccessResponse, tc.ExpectedErrorResponse
+				},
+				GetVehiclesFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedCallCount},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			result, err := GetVehiclesService(context.Background(), 1, swapi.PageSize)
+
+			if tc.IsErrorFlow {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.ExpectedErrorResponse, err)
+				assert.Equal(t, tc.ExpectedStatusCode, errors.Status(err))
+			} else {
+				assert.Nil(t, err)
+				assert.NotNil(t, result)
+				assert.Equal(t, tc.ExpectedSuccessResponse, result)
+			}
+		})
+	}
+}
+
+func TestGetAllStarshipsService(t *testing.T) {
+
+	type TestCase struct {
+		Name                    string
+		IsErrorFlow             bool
+		ExpectedSuccessResponse models.Starships
+		ExpectedErrorResponse   error
+		ExpectedCallCount       int
+		ExpectedStatusCode      int
+	}
+
+	testCases := []TestCase{
+		{
+			Name: "Success",
+			ExpectedSuccessResponse: models.Starships{
+				Count: 1,
+				Results: []models.Starship{
+					{Name: "CR90 corvette"},
+				},
+			},
+			ExpectedCallCount: 1,
+		},
+		{
+			Name:                  "Internal Server Error",
+			IsErrorFlow:           true,
+			ExpectedErrorResponse: errors.NewInternal(),
+			ExpectedCallCount:     1,
+			ExpectedStatusCode:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create mock client
+			swapiMock := swapi.MockClient{
+				GetAllStarshipsFunc: func(ctx context.Context) (models.Starships, error) {
+					return tc.ExpectedSuccessResponse, tc.ExpectedErrorResponse
+				},
+				GetAllStarshipsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedCallCount},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			result, err := GetAllStarshipsService(context.Background())
+
+			if tc.IsErrorFlow {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.ExpectedErrorResponse, err)
+				assert.Equal(t, tc.ExpectedStatusCode, errors.Status(err))
+			} else {
+				assert.Nil(t, err)
+				assert.NotNil(t, result)
+				assert.Equal(t, tc.ExpectedSuccessResponse, result)
+			}
+		})
+	}
+}
+
+func TestGetAllPeopleService(t *testing.T) {
+
+	type TestCase struct {
+		Name                    string
+		IsErrorFlow             bool
+		ExpectedSuccessResponse models.PeopleList
+		ExpectedErrorResponse   error
+		ExpectedCallCount       int
+		ExpectedStatusCode      int
+	}
+
+	testCases := []TestCase{
+		{
+			Name: "Success",
+			ExpectedSuccessResponse: models.PeopleList{
+				Count: 1,
+				Results: []models.People{
+					{Name: "R2-D2"},
+				},
+			},
+			ExpectedCallCount: 1,
+		},
+		{
+			Name:                  "Internal Server Error",
+			IsErrorFlow:           true,
+			ExpectedErrorResponse: errors.NewInternal(),
+			ExpectedCallCount:     1,
+			ExpectedStatusCode:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create mock client
+			swapiMock := swapi.MockClient{
+				GetAllPeopleFunc: func(ctx context.Context) (models.PeopleList, error) {
+					return tc.ExpectedSuccessResponse, tc.ExpectedErrorResponse
+				},
+				GetAllPeopleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: tc.ExpectedCallCount},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			result, err := GetAllPeopleService(context.Background())
+
+			if tc.IsErrorFlow {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.ExpectedErrorResponse, err)
+				assert.Equal(t, tc.ExpectedStatusCode, errors.Status(err))
+			} else {
+				assert.Nil(t, err)
+				assert.NotNil(t, result)
+				assert.Equal(t, tc.ExpectedSuccessResponse, result)
+			}
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+
+	type TestCase struct {
+		Name                  string
+		Page                  int
+		PageSize              int
+		ExpectedSuccessResult []int
+		ExpectedErrorResponse error
+	}
+
+	items := []int{1, 2, 3, 4, 5, 6, 7}
+
+	testCases := []TestCase{
+		{
+			Name:                  "First page",
+			Page:                  1,
+			PageSize:              3,
+			ExpectedSuccessResult: []int{1, 2, 3},
+		},
+		{
+			Name:                  "Last partial page",
+			Page:                  3,
+			PageSize:              3,
+			ExpectedSuccessResult: []int{7},
+		},
+		{
+			Name:                  "Page size bigger than collection",
+			Page:                  1,
+			PageSize:              20,
+			ExpectedSuccessResult: items,
+		},
+		{
+			Name:                  "Out of range",
+			Page:                  4,
+			PageSize:              3,
+			ExpectedErrorResponse: errors.NewNotFound("starships", ""),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			result, err := paginate(items, tc.Page, tc.PageSize, "starships")
+
+			assert.Equal(t, tc.ExpectedErrorResponse, err)
+			assert.Equal(t, tc.ExpectedSuccessResult, result)
+		})
+	}
+
+	t.Run("Empty collection", func(t *testing.T) {
+		result, err := paginate([]int{}, 1, 5, "starships")
+
+		assert.Nil(t, err)
+		assert.Empty(t, result)
+	})
+}
+
+func TestSearchPeopleService(t *testing.T) {
+
+	type TestCase struct {
+		Name                    string
+		IsErrorFlow             bool
+		Page                    int
+		PageSize                int
+		ExpectedSuccessResponse models.PeopleList
+		ExpectedErrorResponse   error
+		ExpectedStatusCode      int
+	}
+
+	people := []models.People{{Name: "Luke Skywalker"}, {Name: "Anakin Skywalker"}, {Name: "Shmi Skywalker"}}
+
+	testCases := []TestCase{
+		{
+			Name:                    "Every match",
+			Page:                    1,
+			PageSize:                0,
+			ExpectedSuccessResponse: models.PeopleList{Count: 3, Results: people},
+		},
+		{
+			Name:                    "Paginated",
+			Page:                    2,
+			PageSize:                2,
+			ExpectedSuccessResponse: models.PeopleList{Count: 3, Results: people[2:]},
+		},
+		{
+			Name:                  "Out of range",
+			IsErrorFlow:           true,
+			Page:                  3,
+			PageSize:              2,
+			ExpectedErrorResponse: errors.NewNotFound("people", ""),
+			ExpectedStatusCode:    http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			// Create mock client
+			swapiMock := swapi.MockClient{
+				SearchPeopleFunc: func(ctx context.Context, query string) (models.PeopleList, error) {
+					assert.Equal(t, "skywalker", query)
+					return models.PeopleList{Count: len(people), Results: people}, nil
+				},
+				SearchPeopleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+			}
+
+			swapiMock.Use()
+			defer mockeable.CleanUpAndAssertControls(t, &swapiMock)
+
+			result, err := SearchPeopleService(context.Background(), "skywalker", tc.Page, tc.PageSize)
+
+			if tc.IsErrorFlow {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.ExpectedErrorResponse, err)
+				assert.Equal(t, tc.ExpectedStatusCode, errors.Status(err))
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, tc.ExpectedSuccessResponse, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,354 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MemoryExporter keeps every span, for tests
+type MemoryExporter struct {
+	mu    sync.Mutex
+	spans []*Span
+}
+
+func NewMemoryExporter() *MemoryExporter {
+	return &MemoryExporter{}
+}
+
+func (e *MemoryExporter) Export(span *Span) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = append(e.spans, span)
+}
+
+// Spans returns the exported spans in the order they ended
+func (e *MemoryExporter) Spans() []*Span {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]*Span(nil), e.spans...)
+}
+
+func (e *MemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = nil
+}
+
+// WriterExporter writes every span as a JSON line
+type WriterExporter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func NewWriterExporter(out io.Writer) *WriterExporter {
+	return &WriterExporter{out: out}
+}
+
+type spanJSON struct {
+	Name          string     `json:"name"`
+	Kind          string     `json:"kind"`
+	TraceID       string     `json:"trace_id"`
+	SpanID        string     `json:"span_id"`
+	ParentSpanID  string     `json:"parent_span_id,omitempty"`
+	Start         time.Time  `json:"start"`
+	End           time.Time  `json:"end"`
+	DurationMs    float64    `json:"duration_ms"`
+	Attributes    Attributes `json:"attributes,omitempty"`
+	Status        string     `json:"status"`
+	StatusMessage string     `json:"status_message,omitempty"`
+}
+
+func (e *WriterExporter) Export(span *Span) {
+	record := spanJSON{
+		Name:          span.Name,
+		Kind:          span.Kind.String(),
+		TraceID:       span.Context.TraceID.String(),
+		SpanID:        span.Context.SpanID.String(),
+		Start:         span.StartTime.UTC(),
+		End:           span.EndTime.UTC(),
+		DurationMs:    float64(span.EndTime.Sub(span.StartTime).Microseconds()) / 1000,
+		Attributes:    span.Attributes,
+		Status:        span.Status.String(),
+		StatusMessage: span.StatusMessage,
+	}
+
+	if span.Parent.IsValid() {
+		record.ParentSpanID = span.Parent.String()
+	}
+
+	line, err := json.Marshal(record)
+
+	if err != nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.out.Write(append(line, '\n'))
+}
+
+type OTLPOptions struct {
+	// Endpoint is the OTLP/HTTP traces URL of the collector, such as
+	// http://localhost:4318/v1/traces
+	Endpoint    string
+	ServiceName string
+	// BatchSize spans are sent at once, or whatever ended during
+	// FlushInterval
+	BatchSize     int
+	FlushInterval time.Duration
+	// QueueSize bounds the spans waiting to be sent, later ones are dropped
+	QueueSize int
+	Timeout   time.Duration
+	// OnError is called when a batch can't be sent
+	OnError func(err error)
+}
+
+func DefaultOTLPOptions() OTLPOptions {
+	return OTLPOptions{
+		Endpoint:      "http://localhost:4318/v1/traces",
+		ServiceName:   "swapi",
+		BatchSize:     512,
+		FlushInterval: 5 * time.Second,
+		QueueSize:     2048,
+		Timeout:       10 * time.Second,
+	}
+}
+
+// OTLPExporter sends spans in batches to an OpenTelemetry collector using
+// OTLP/HTTP with the JSON encoding. Spans are queued, so Export never waits
+// for the collector.
+type OTLPExporter struct {
+	options OTLPOptions
+	client  *http.Client
+
+	queue     chan *Span
+	stop      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+func NewOTLPExporter(options OTLPOptions) *OTLPExporter {
+	e := &OTLPExporter{
+		options: options,
+		client:  &http.Client{Timeout: options.Timeout},
+		queue:   make(chan *Span, options.QueueSize),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	go e.run()
+
+	return e
+}
+
+func (e *OTLPExporter) Export(span *Span) {
+	select {
+	case e.queue <- span:
+	default:
+		e.fail(fmt.Errorf("span queue full, dropping span %q", span.Name))
+	}
+}
+
+// Close sends the queued spans and stops the exporter. It is safe to call
+// more than once.
+func (e *OTLPExporter) Close() error {
+	e.closeOnce.Do(func() {
+		close(e.stop)
+	})
+
+	<-e.stopped
+
+	return nil
+}
+
+func (e *OTLPExporter) run() {
+	defer close(e.stopped)
+
+	ticker := time.NewTicker(e.options.FlushInterval)
+	defer ticker.Stop()
+
+	var batch []*Span
+
+	flush := func() {
+		if len(batch) > 0 {
+			e.send(batch)
+			batch = nil
+		}
+	}
+
+	for {
+		select {
+		case span := <-e.queue:
+			batch = append(batch, span)
+
+			if len(batch) >= e.options.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-e.stop:
+			for {
+				select {
+				case span := <-e.queue:
+					batch = append(batch, span)
+
+					if len(batch) >= e.options.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *OTLPExporter) send(spans []*Span) {
+	body, err := json.Marshal(otlpRequest(e.options.ServiceName, spans))
+
+	if err != nil {
+		e.fail(err)
+		return
+	}
+
+	res, err := e.client.Post(e.options.Endpoint, "application/json", bytes.NewReader(body))
+
+	if err != nil {
+		e.fail(err)
+		return
+	}
+
+	io.Copy(ioutil.Discard, res.Body)
+	res.Body.Close()
+
+	if res.StatusCode/100 != 2 {
+		e.fail(fmt.Errorf("collector responded with status %d", res.StatusCode))
+	}
+}
+
+func (e *OTLPExporter) fail(err error) {
+	if e.options.OnError != nil {
+		e.options.OnError(err)
+	}
+}
+
+// The OTLP JSON encoding of an ExportTraceServiceRequest. Ids are hex
+// encoded and 64 bit integers are strings, as the protocol requires.
+type (
+	otlpTraces struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              int            `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		Status            otlpStatus     `json:"status"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	}
+	otlpKeyValue struct {
+		Key   string                 `json:"key"`
+		Value map[string]interface{} `json:"value"`
+	}
+)
+
+func otlpRequest(serviceName string, spans []*Span) otlpTraces {
+	encoded := make([]otlpSpan, 0, len(spans))
+
+	for _, span := range spans {
+		s := otlpSpan{
+			TraceID:           span.Context.TraceID.String(),
+			SpanID:            span.Context.SpanID.String(),
+			Name:              span.Name,
+			Kind:              int(span.Kind),
+			StartTimeUnixNano: strconv.FormatInt(span.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.EndTime.UnixNano(), 10),
+			Attributes:        otlpAttributes(span.Attributes),
+			Status:            otlpStatus{Code: int(span.Status), Message: span.StatusMessage},
+		}
+
+		if span.Parent.IsValid() {
+			s.ParentSpanID = span.Parent.String()
+		}
+
+		encoded = append(encoded, s)
+	}
+
+	return otlpTraces{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource:   otlpResource{Attributes: otlpAttributes(Attributes{"service.name": serviceName})},
+			ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: "swapi"}, Spans: encoded}},
+		}},
+	}
+}
+
+// otlpAttributes encodes attributes sorted by key, values of other types than
+// strings, booleans and numbers are sent as strings
+func otlpAttributes(attributes Attributes) []otlpKeyValue {
+	keys := make([]string, 0, len(attributes))
+
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	result := make([]otlpKeyValue, 0, len(keys))
+
+	for _, key := range keys {
+		var value map[string]interface{}
+
+		switch v := attributes[key].(type) {
+		case string:
+			value = map[string]interface{}{"stringValue": v}
+		case bool:
+			value = map[string]interface{}{"boolValue": v}
+		case int:
+			value = map[string]interface{}{"intValue": strconv.Itoa(v)}
+		case int64:
+			value = map[string]interface{}{"intValue": strconv.FormatInt(v, 10)}
+		case float64:
+			value = map[string]interface{}{"doubleValue": v}
+		default:
+			value = map[string]interface{}{"stringValue": fmt.Sprint(v)}
+		}
+
+		result = append(result, otlpKeyValue{Key: key, Value: value})
+	}
+
+	return result
+}
@@ -0,0 +1,186 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestSpan returns an ended span with fixed ids and times
+func newTestSpan(name string, parent bool) *Span {
+	span := &Span{
+		Name:       name,
+		Kind:       KindClient,
+		StartTime:  time.Date(2022, 5, 4, 12, 0, 0, 0, time.UTC),
+		EndTime:    time.Date(2022, 5, 4, 12, 0, 0, 1500000, time.UTC),
+		Attributes: Attributes{"swapi.resource": "people", "swapi.id": 1, "http.status_code": 200},
+		Status:     StatusError,
+	}
+
+	copy(span.Context.TraceID[:], []byte("0123456789abcdef"))
+	copy(span.Context.SpanID[:], []byte("spanid01"))
+
+	if parent {
+		copy(span.Parent[:], []byte("parent01"))
+	}
+
+	span.StatusMessage = "failed"
+
+	return span
+}
+
+func TestWriterExporter(t *testing.T) {
+	var out bytes.Buffer
+
+	NewWriterExporter(&out).Export(newTestSpan("swapi GET /people", true))
+
+	assert.JSONEq(t, `{
+		"name": "swapi GET /people",
+		"kind": "client",
+		"trace_id": "30313233343536373839616263646566",
+		"span_id": "7370616e69643031",
+		"parent_span_id": "706172656e743031",
+		"start": "2022-05-04T12:00:00Z",
+		"end": "2022-05-04T12:00:00.0015Z",
+		"duration_ms": 1.5,
+		"attributes": {"http.status_code": 200, "swapi.id": 1, "swapi.resource": "people"},
+		"status": "error",
+		"status_message": "failed"
+	}`, out.String())
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+
+	collector := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		body, _ := ioutil.ReadAll(r.Body)
+
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+	}))
+	defer collector.Close()
+
+	t.Run("Close flushes", func(t *testing.T) {
+		options := DefaultOTLPOptions()
+		options.Endpoint = collector.URL + "/v1/traces"
+
+		exporter := NewOTLPExporter(options)
+		exporter.Export(newTestSpan("swapi GET /people", true))
+		exporter.Export(newTestSpan("GET /api/v1/people/{id}", false))
+
+		assert.Nil(t, exporter.Close())
+		assert.Nil(t, exporter.Close(), "closing twice is harmless")
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		assert.Len(t, bodies, 1)
+		assert.JSONEq(t, `{"resourceSpans": [{
+			"resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "swapi"}}]},
+			"scopeSpans": [{
+				"scope": {"name": "swapi"},
+				"spans": [
+					{
+						"traceId": "30313233343536373839616263646566",
+						"spanId": "7370616e69643031",
+						"parentSpanId": "706172656e743031",
+						"name": "swapi GET /people",
+						"kind": 3,
+						"startTimeUnixNano": "1651665600000000000",
+						"endTimeUnixNano": "1651665600001500000",
+						"attributes": [
+							{"key": "http.status_code", "value": {"intValue": "200"}},
+							{"key": "swapi.id", "value": {"intValue": "1"}},
+							{"key": "swapi.resource", "value": {"stringValue": "people"}}
+						],
+						"status": {"code": 2, "message": "failed"}
+					},
+					{
+						"traceId": "30313233343536373839616263646566",
+						"spanId": "7370616e69643031",
+						"name": "GET /api/v1/people/{id}",
+						"kind": 3,
+						"startTimeUnixNano": "1651665600000000000",
+						"endTimeUnixNano": "1651665600001500000",
+						"attributes": [
+							{"key": "http.status_code", "value": {"intValue": "200"}},
+							{"key": "swapi.id", "value": {"intValue": "1"}},
+							{"key": "swapi.resource", "value": {"stringValue": "people"}}
+						],
+						"status": {"code": 2, "message": "failed"}
+					}
+				]
+			}]
+		}]}`, bodies[0])
+	})
+
+	t.Run("Batches", func(t *testing.T) {
+		mu.Lock()
+		bodies = nil
+		mu.Unlock()
+
+		options := DefaultOTLPOptions()
+		options.Endpoint = collector.URL + "/v1/traces"
+		options.BatchSize = 2
+
+		exporter := NewOTLPExporter(options)
+
+		for i := 0; i < 5; i++ {
+			exporter.Export(newTestSpan("span", false))
+		}
+
+		exporter.Close()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		var sizes []int
+
+		for _, body := range bodies {
+			var request otlpTraces
+			json.Unmarshal([]byte(body), &request)
+			sizes = append(sizes, len(request.ResourceSpans[0].ScopeSpans[0].Spans))
+		}
+
+		assert.Equal(t, []int{2, 2, 1}, sizes)
+	})
+
+	t.Run("Collector errors", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer failing.Close()
+
+		var errs []error
+
+		options := DefaultOTLPOptions()
+		options.Endpoint = failing.URL
+		options.QueueSize = 1
+		options.OnError = func(err error) {
+			errs = append(errs, err)
+		}
+
+		exporter := NewOTLPExporter(options)
+		tracer := New(exporter)
+
+		_, span := tracer.Start(context.Background(), KindInternal, "span", nil)
+		span.End(nil)
+
+		exporter.Close()
+
+		assert.Equal(t, "collector responded with status 503", errs[len(errs)-1].Error())
+	})
+}
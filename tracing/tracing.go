@@ -0,0 +1,335 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TraceparentHeader carries the span context in the W3C Trace Context format
+const TraceparentHeader = "traceparent"
+
+type TraceID [16]byte
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+type SpanID [8]byte
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// SpanContext identifies a span across process boundaries
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Traceparent formats sc as a version 00 traceparent header value
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+
+	if sc.Sampled {
+		flags = "01"
+	}
+
+	return fmt.Sprintf("00-%s-%s-%s", sc.TraceID, sc.SpanID, flags)
+}
+
+// ParseTraceparent reads a traceparent header value. Versions other than 00
+// are read as 00, as the spec asks, as long as they start the same way.
+func ParseTraceparent(value string) (SpanContext, bool) {
+	var sc SpanContext
+
+	if len(value) < 55 || (len(value) > 55 && value[55] != '-') {
+		return sc, false
+	}
+
+	if value[2] != '-' || value[35] != '-' || value[52] != '-' {
+		return sc, false
+	}
+
+	version, err := hex.DecodeString(value[0:2])
+
+	if err != nil || version[0] == 0xff || (version[0] == 0 && len(value) != 55) {
+		return sc, false
+	}
+
+	if _, err := hex.Decode(sc.TraceID[:], []byte(value[3:35])); err != nil {
+		return sc, false
+	}
+
+	if _, err := hex.Decode(sc.SpanID[:], []byte(value[36:52])); err != nil {
+		return sc, false
+	}
+
+	flags, err := hex.DecodeString(value[53:55])
+
+	if err != nil || !sc.IsValid() {
+		return sc, false
+	}
+
+	sc.Sampled = flags[0]&1 == 1
+
+	return sc, true
+}
+
+type Kind int
+
+const (
+	KindInternal Kind = iota + 1
+	KindServer
+	KindClient
+)
+
+var kindNames = map[Kind]string{
+	KindInternal: "internal",
+	KindServer:   "server",
+	KindClient:   "client",
+}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+type Status int
+
+const (
+	StatusUnset Status = iota
+	StatusOK
+	StatusError
+)
+
+var statusNames = map[Status]string{
+	StatusUnset: "unset",
+	StatusOK:    "ok",
+	StatusError: "error",
+}
+
+func (s Status) String() string {
+	return statusNames[s]
+}
+
+// Attributes are the structured values of a span
+type Attributes map[string]interface{}
+
+// Span is a timed operation of a trace. Its fields must only be read once it
+// has ended, its methods are safe to call on a nil *Span, which is what Start
+// returns when tracing is disabled.
+type Span struct {
+	Name          string
+	Kind          Kind
+	Context       SpanContext
+	Parent        SpanID
+	StartTime     time.Time
+	EndTime       time.Time
+	Attributes    Attributes
+	Status        Status
+	StatusMessage string
+
+	tracer *Tracer
+	mu     sync.Mutex
+	ended  bool
+}
+
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+
+	return s.Context
+}
+
+func (s *Span) SetName(name string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Name = name
+}
+
+func (s *Span) SetAttributes(attributes Attributes) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, value := range attributes {
+		s.Attributes[key] = value
+	}
+}
+
+// SetStatus sets the status of the span, with a message for errors
+func (s *Span) SetStatus(status Status, message string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Status = status
+	s.StatusMessage = message
+}
+
+// End records err, if any, as the status of the span and exports it. Only
+// the first call has any effect.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+
+	s.ended = true
+	s.EndTime = s.tracer.now()
+
+	if err != nil {
+		s.Status = StatusError
+		s.StatusMessage = err.Error()
+	}
+
+	s.mu.Unlock()
+
+	if s.Context.Sampled {
+		s.tracer.exporter.Export(s)
+	}
+}
+
+// Exporter sends ended spans to a backend. Export is called once per span,
+// concurrently, and must not block on the backend.
+type Exporter interface {
+	Export(span *Span)
+}
+
+// Tracer creates spans and hands them to its exporter once ended
+type Tracer struct {
+	exporter Exporter
+	now      func() time.Time
+}
+
+func New(exporter Exporter) *Tracer {
+	return &Tracer{exporter: exporter, now: time.Now}
+}
+
+// Instance is the tracer used by Start. Tracing is disabled while it's nil.
+var Instance *Tracer
+
+// Close flushes and stops the exporter, if it needs to
+func (t *Tracer) Close() error {
+	if t == nil {
+		return nil
+	}
+
+	if closer, ok := t.exporter.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+type spanKey struct{}
+
+type remoteKey struct{}
+
+// ContextWithRemote returns a copy of ctx whose next span continues the trace
+// of a span from another process, typically read from a traceparent header
+func ContextWithRemote(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// SpanFromContext returns the current span of ctx, nil if there is none
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+
+	return span
+}
+
+// Start starts a span with the Instance tracer, as a child of the current
+// span of ctx or of its remote parent, and returns a copy of ctx holding it.
+// It returns ctx itself and a nil span when tracing is disabled.
+func Start(ctx context.Context, kind Kind, name string, attributes Attributes) (context.Context, *Span) {
+	return Instance.Start(ctx, kind, name, attributes)
+}
+
+func (t *Tracer) Start(ctx context.Context, kind Kind, name string, attributes Attributes) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+
+	span := &Span{
+		Name:       name,
+		Kind:       kind,
+		StartTime:  t.now(),
+		Attributes: Attributes{},
+		tracer:     t,
+	}
+
+	for key, value := range attributes {
+		span.Attributes[key] = value
+	}
+
+	if parent := SpanFromContext(ctx); parent != nil {
+		span.Context.TraceID = parent.Context.TraceID
+		span.Context.Sampled = parent.Context.Sampled
+		span.Parent = parent.Context.SpanID
+	} else if remote, ok := ctx.Value(remoteKey{}).(SpanContext); ok && remote.IsValid() {
+		span.Context.TraceID = remote.TraceID
+		span.Context.Sampled = remote.Sampled
+		span.Parent = remote.SpanID
+	} else {
+		rand.Read(span.Context.TraceID[:])
+		span.Context.Sampled = true
+	}
+
+	rand.Read(span.Context.SpanID[:])
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// Inject sets the traceparent header of the current span of ctx, if any
+func Inject(ctx context.Context, header http.Header) {
+	if span := SpanFromContext(ctx); span != nil {
+		header.Set(TraceparentHeader, span.Context.Traceparent())
+	}
+}
+
+// Extract returns a copy of ctx continuing the trace of the traceparent
+// header, when it is valid
+func Extract(ctx context.Context, header http.Header) context.Context {
+	if sc, ok := ParseTraceparent(header.Get(TraceparentHeader)); ok {
+		return ContextWithRemote(ctx, sc)
+	}
+
+	return ctx
+}
@@ -0,0 +1,171 @@
+package tracing
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTraceparent(t *testing.T) {
+
+	type TestCase struct {
+		Name            string
+		Value           string
+		ExpectedOK      bool
+		ExpectedSampled bool
+	}
+
+	testCases := []TestCase{
+		{
+			Name:            "Sampled",
+			Value:           "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			ExpectedOK:      true,
+			ExpectedSampled: true,
+		},
+		{
+			Name:       "Not sampled",
+			Value:      "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+			ExpectedOK: true,
+		},
+		{
+			Name:            "Future version with more fields",
+			Value:           "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+			ExpectedOK:      true,
+			ExpectedSampled: true,
+		},
+		{
+			Name:  "Version 00 with more fields",
+			Value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		},
+		{
+			Name:  "Invalid version",
+			Value: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		},
+		{
+			Name:  "Zero trace id",
+			Value: "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		},
+		{
+			Name:  "Zero span id",
+			Value: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		},
+		{
+			Name:  "Not hex",
+			Value: "00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01",
+		},
+		{
+			Name:  "Missing",
+			Value: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			sc, ok := ParseTraceparent(tc.Value)
+
+			assert.Equal(t, tc.ExpectedOK, ok)
+
+			if tc.ExpectedOK {
+				assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String())
+				assert.Equal(t, "00f067aa0ba902b7", sc.SpanID.String())
+				assert.Equal(t, tc.ExpectedSampled, sc.Sampled)
+			}
+		})
+	}
+
+	sc, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sc.Traceparent())
+}
+
+func TestTracer(t *testing.T) {
+	t.Run("Parent and child", func(t *testing.T) {
+		exporter := NewMemoryExporter()
+		tracer := New(exporter)
+
+		ctx, parent := tracer.Start(context.Background(), KindServer, "parent", Attributes{"swapi.resource": "people"})
+		_, child := tracer.Start(ctx, KindClient, "child", nil)
+
+		child.SetAttributes(Attributes{"http.status_code": 404})
+		child.End(stderrors.New("not found"))
+		parent.End(nil)
+		parent.End(stderrors.New("ignored, already ended"))
+
+		spans := exporter.Spans()
+
+		assert.Len(t, spans, 2)
+		assert.Equal(t, "child", spans[0].Name)
+		assert.Equal(t, parent.Context.TraceID, spans[0].Context.TraceID)
+		assert.Equal(t, parent.Context.SpanID, spans[0].Parent)
+		assert.Equal(t, StatusError, spans[0].Status)
+		assert.Equal(t, "not found", spans[0].StatusMessage)
+		assert.Equal(t, Attributes{"http.status_code": 404}, spans[0].Attributes)
+
+		assert.Equal(t, "parent", spans[1].Name)
+		assert.False(t, spans[1].Parent.IsValid(), "root span")
+		assert.True(t, spans[1].Context.Sampled)
+		assert.Equal(t, StatusUnset, spans[1].Status)
+		assert.Equal(t, Attributes{"swapi.resource": "people"}, spans[1].Attributes)
+	})
+
+	t.Run("Remote parent", func(t *testing.T) {
+		exporter := NewMemoryExporter()
+		tracer := New(exporter)
+
+		header := http.Header{}
+		header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		ctx, span := tracer.Start(Extract(context.Background(), header), KindServer, "server", nil)
+
+		outgoing := http.Header{}
+		Inject(ctx, outgoing)
+		span.End(nil)
+
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.Context.TraceID.String())
+		assert.Equal(t, "00f067aa0ba902b7", span.Parent.String())
+		assert.Equal(t, span.Context.Traceparent(), outgoing.Get(TraceparentHeader))
+		assert.Len(t, exporter.Spans(), 1)
+	})
+
+	t.Run("Remote parent not sampled", func(t *testing.T) {
+		exporter := NewMemoryExporter()
+		tracer := New(exporter)
+
+		header := http.Header{}
+		header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+
+		ctx, span := tracer.Start(Extract(context.Background(), header), KindServer, "server", nil)
+
+		outgoing := http.Header{}
+		Inject(ctx, outgoing)
+		span.End(nil)
+
+		assert.Empty(t, exporter.Spans())
+		assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+span.Context.SpanID.String()+"-00", outgoing.Get(TraceparentHeader), "the decision is propagated")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		var tracer *Tracer
+
+		ctx, span := tracer.Start(context.Background(), KindInternal, "noop", nil)
+
+		assert.Nil(t, span)
+		assert.Equal(t, context.Background(), ctx)
+
+		assert.NotPanics(t, func() {
+			span.SetName("noop")
+			span.SetAttributes(Attributes{"key": "value"})
+			span.SetStatus(StatusError, "failed")
+			span.End(nil)
+		})
+
+		header := http.Header{}
+		Inject(ctx, header)
+
+		assert.Empty(t, header)
+		assert.Nil(t, tracer.Close())
+	})
+}